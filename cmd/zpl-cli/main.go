@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Renatinjr/zpl-go/zpl"
+)
+
+func main() {
+	reader := bufio.NewReader(os.Stdin)
+
+	// Choose how the printer is connected
+	fmt.Println("Select connection type:")
+	fmt.Println("1. USB")
+	fmt.Println("2. Network")
+	fmt.Println("3. Serial")
+	fmt.Print("Choice: ")
+
+	var cfg zpl.Config
+	switch readLine(reader) {
+	case "1":
+		cfg.Type = zpl.TypeUSB
+	case "2":
+		cfg.Type = zpl.TypeNetwork
+		fmt.Print("Printer address (host[:port]): ")
+		cfg.Address = readLine(reader)
+	case "3":
+		cfg.Type = zpl.TypeSerial
+		fmt.Print("Serial port (default USB001): ")
+		cfg.Port = readLine(reader)
+		if cfg.Port == "" {
+			cfg.Port = "USB001"
+		}
+		fmt.Print("Baud rate (default 9600): ")
+		if s := readLine(reader); s != "" {
+			baud, err := strconv.Atoi(s)
+			if err != nil {
+				log.Fatalf("Invalid baud rate: %v", err)
+			}
+			cfg.Baud = baud
+		}
+	default:
+		log.Fatal("Invalid choice")
+	}
+
+	printer, err := zpl.NewPrinter(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to printer: %v", err)
+	}
+	defer printer.Close()
+
+	for {
+		fmt.Println()
+		fmt.Println("1. Print test label")
+		fmt.Println("2. Send custom ZPL")
+		fmt.Println("3. Send ZPL file")
+		fmt.Println("4. Exit")
+		fmt.Print("Choice: ")
+
+		choice, err := reader.ReadString('\n')
+		if err != nil && choice == "" {
+			return
+		}
+
+		switch strings.TrimSpace(choice) {
+		case "1":
+			send(printer, zpl.TestLabel("", ""))
+		case "2":
+			fmt.Print("ZPL: ")
+			send(printer, readLine(reader))
+		case "3":
+			fmt.Print("File: ")
+			if err := zpl.SendFile(printer, readLine(reader)); err != nil {
+				fmt.Printf("Failed to send file: %v\n", err)
+				break
+			}
+			fmt.Println("File sent successfully")
+		case "4":
+			return
+		default:
+			fmt.Println("Invalid choice")
+		}
+	}
+}
+
+func send(printer zpl.PrinterConnection, label string) {
+	if err := printer.SendZPL(label); err != nil {
+		fmt.Printf("Failed to send ZPL: %v\n", err)
+		return
+	}
+	fmt.Println("Label sent successfully")
+}
+
+func readLine(reader *bufio.Reader) string {
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
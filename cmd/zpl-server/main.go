@@ -0,0 +1,47 @@
+// Command zpl-server is a print gateway: it forwards ZPL posted over HTTP
+// to a printer, see zpl.NewHTTPHandler.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Renatinjr/zpl-go/zpl"
+)
+
+func main() {
+	var cfg zpl.Config
+	listen := flag.String("listen", ":8080", "address to serve HTTP on")
+	strict := flag.Bool("strict", false, "reject payloads that fail ZPL validation")
+	flag.StringVar(&cfg.Type, "type", zpl.TypeNetwork, "printer connection: usb, network or serial")
+	flag.StringVar(&cfg.Address, "addr", "", "printer address (host[:port]) for network printers")
+	flag.StringVar(&cfg.Port, "port", "", "serial port for serial printers")
+	flag.IntVar(&cfg.Baud, "baud", zpl.DefaultBaudRate, "baud rate for serial printers")
+	flag.Parse()
+
+	var opts []zpl.Option
+	if *strict {
+		opts = append(opts, zpl.WithStrictValidation())
+	}
+	printer, err := zpl.NewPrinter(cfg, opts...)
+	if err != nil {
+		log.Fatalf("Failed to connect to printer: %v", err)
+	}
+	defer printer.Close()
+
+	// Bound every phase of a request, so slow clients cannot hold
+	// connections open
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           zpl.NewHTTPHandler(printer),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+	log.Printf("Serving on %s", *listen)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+}
@@ -1,11 +1,12 @@
-module github.com/Renatinjr/zpl-go.git
+module github.com/Renatinjr/zpl-go
 
 go 1.23.2
 
 require (
-	github.com/creack/goselect v0.1.2 // indirect
-	github.com/google/gousb v1.1.3 // indirect
-	github.com/tarm/serial v0.0.0-20180830185346-98f6abe2eb07 // indirect
-	go.bug.st/serial v1.6.4 // indirect
-	golang.org/x/sys v0.32.0 // indirect
+	github.com/google/gousb v1.1.3
+	go.bug.st/serial v1.6.4
+	golang.org/x/image v0.23.0
+	golang.org/x/sys v0.32.0
 )
+
+require github.com/creack/goselect v0.1.2 // indirect
@@ -0,0 +1,230 @@
+package zpl
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Alert is an unsolicited message a printer sends when a condition set up
+// with ^SX or the alerts SGD settings occurs or clears, such as
+//
+//	ERROR CONDITION: PAPER OUT [00000001000000]
+type Alert struct {
+	Kind      string // upper case text before the colon, such as "ERROR CONDITION"
+	Condition string // such as "PAPER OUT" or "PQ JOB COMPLETED"
+	Code      string // the bracketed hex error or warning flags, if any
+	Cleared   bool   // the condition has gone away
+	Raw       string // the message line without its line ending
+}
+
+// alertKinds are the message prefixes that mark a line as an alert.
+var alertKinds = []string{"ALERT", "ERROR CONDITION", "ERROR CLEARED", "WARNING CONDITION", "WARNING CLEARED"}
+
+// ParseAlert parses one line of printer output as an alert message,
+// reporting false if it is not one.
+func ParseAlert(line string) (Alert, bool) {
+	raw := strings.TrimRight(line, "\r\n")
+	kind, rest, ok := strings.Cut(strings.TrimSpace(raw), ":")
+	kind = strings.ToUpper(strings.TrimSpace(kind))
+	if !ok || !isAlertKind(kind) {
+		return Alert{}, false
+	}
+	a := Alert{Kind: kind, Cleared: strings.HasSuffix(kind, "CLEARED"), Raw: raw}
+	a.Condition = strings.TrimSpace(rest)
+	if i := strings.LastIndexByte(a.Condition, '['); i >= 0 && strings.HasSuffix(a.Condition, "]") {
+		a.Code = a.Condition[i+1 : len(a.Condition)-1]
+		a.Condition = strings.TrimSpace(a.Condition[:i])
+	}
+	return a, true
+}
+
+func isAlertKind(kind string) bool {
+	for _, k := range alertKinds {
+		if kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// mayBeAlert reports whether the start of a line, not yet complete, could
+// still turn out to be an alert.
+func mayBeAlert(start []byte) bool {
+	s := strings.ToUpper(strings.TrimLeft(string(start), " "))
+	for _, k := range alertKinds {
+		if strings.HasPrefix(k+":", s) || strings.HasPrefix(s, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertChannel starts reading p's input in the background and returns a
+// channel of the alert messages found in it. Everything else is kept for
+// queries such as Status and GetVar, which go on working alongside, as do
+// Read calls. Further calls return the same channel.
+//
+// The channel is closed once the connection is closed or lost, including
+// when a NetworkPrinter reconnects or a USBPrinter is reopened; call
+// AlertChannel again for a new one. Alerts arriving while the channel is
+// full are dropped. The printer only sends alerts it has been told to, for
+// example with ^SXA,A,Y,Y for paper out on the serial and USB port. p must
+// be able to read responses back.
+func AlertChannel(p PrinterConnection) (<-chan Alert, error) {
+	r, ok := asResponder(p)
+	if !ok {
+		return nil, ErrNotBidirectional
+	}
+	release := r.acquire()
+	defer release()
+	a, err := r.alertReader()
+	if err != nil {
+		return nil, err
+	}
+	return a.alerts, nil
+}
+
+// Sizes of the alert channel and of the backlog of input kept for the
+// next query.
+const (
+	alertBuffer    = 16
+	maxReplyBuffer = 64 << 10
+)
+
+// alertReader is the only reader of a printer's input once AlertChannel
+// has been called. It sends alert lines to the channel and buffers the
+// rest for readDeadline.
+type alertReader struct {
+	alerts chan Alert
+	ready  chan struct{} // signalled when data arrives
+	done   chan struct{} // closed once reading has stopped
+
+	mu        sync.Mutex
+	data      []byte // input for queries and Read
+	pending   []byte // start of a line that may be an alert
+	lineStart bool   // the next byte starts a line
+	err       error  // why reading stopped
+}
+
+// newAlertReader starts reading with read until it fails for a reason
+// other than a timeout.
+func newAlertReader(read func([]byte) (int, error), opts options) *alertReader {
+	a := &alertReader{
+		alerts:    make(chan Alert, alertBuffer),
+		ready:     make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		lineStart: true,
+	}
+	go func() {
+		defer close(a.alerts)
+		buf := make([]byte, 512)
+		for {
+			n, err := read(buf)
+			if n > 0 {
+				a.feed(buf[:n], opts)
+			}
+			if err != nil && !isTimeout(err) && !errors.Is(err, os.ErrDeadlineExceeded) {
+				opts.log().Debug("alert reader stopped", "err", err)
+				a.mu.Lock()
+				a.err = err
+				a.mu.Unlock()
+				close(a.done)
+				return
+			}
+		}
+	}()
+	return a
+}
+
+// feed sorts newly read input into alerts and query data.
+func (a *alertReader) feed(b []byte, opts options) {
+	a.mu.Lock()
+	buf := append(a.pending, b...)
+	a.pending = nil
+	for len(buf) > 0 {
+		i := bytes.IndexByte(buf, '\n')
+		switch {
+		case !a.lineStart && i < 0:
+			a.data = append(a.data, buf...)
+			buf = nil
+		case !a.lineStart:
+			a.data = append(a.data, buf[:i+1]...)
+			buf = buf[i+1:]
+			a.lineStart = true
+		case i < 0 && mayBeAlert(buf):
+			a.pending = buf
+			buf = nil
+		case i < 0:
+			a.data = append(a.data, buf...)
+			buf = nil
+			a.lineStart = false
+		default:
+			if alert, ok := ParseAlert(string(buf[:i+1])); ok {
+				select {
+				case a.alerts <- alert:
+				default:
+					opts.log().Warn("alert dropped, channel full", "alert", alert.Raw)
+				}
+			} else {
+				a.data = append(a.data, buf[:i+1]...)
+			}
+			buf = buf[i+1:]
+		}
+	}
+	if over := len(a.data) - maxReplyBuffer; over > 0 {
+		a.data = a.data[over:]
+	}
+	a.mu.Unlock()
+	a.signal()
+}
+
+func (a *alertReader) signal() {
+	select {
+	case a.ready <- struct{}{}:
+	default:
+	}
+}
+
+// read takes buffered input into b, waiting until deadline for some to
+// arrive; a zero deadline waits for as long as it takes. A line start held
+// back as a possible alert is handed over once the deadline passes.
+func (a *alertReader) read(b []byte, deadline time.Time) (int, error) {
+	var expired <-chan time.Time
+	if !deadline.IsZero() {
+		t := time.NewTimer(time.Until(deadline))
+		defer t.Stop()
+		expired = t.C
+	}
+	for {
+		a.mu.Lock()
+		if len(a.data) > 0 {
+			n := copy(b, a.data)
+			a.data = a.data[n:]
+			a.mu.Unlock()
+			return n, nil
+		}
+		err := a.err
+		a.mu.Unlock()
+		if err != nil {
+			return 0, err
+		}
+
+		select {
+		case <-a.ready:
+		case <-a.done:
+		case <-expired:
+			a.mu.Lock()
+			if len(a.pending) == 0 {
+				a.mu.Unlock()
+				return 0, os.ErrDeadlineExceeded
+			}
+			a.data, a.pending = append(a.data, a.pending...), nil
+			a.lineStart = false
+			a.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package zpl
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseAlert(t *testing.T) {
+	tests := []struct {
+		line string
+		want Alert
+	}{
+		{"ERROR CONDITION: PAPER OUT [00000001000000]\r\n", Alert{
+			Kind: "ERROR CONDITION", Condition: "PAPER OUT", Code: "00000001000000",
+			Raw: "ERROR CONDITION: PAPER OUT [00000001000000]",
+		}},
+		{"ERROR CLEARED: HEAD OPEN [00000000000004]\n", Alert{
+			Kind: "ERROR CLEARED", Condition: "HEAD OPEN", Code: "00000000000004", Cleared: true,
+			Raw: "ERROR CLEARED: HEAD OPEN [00000000000004]",
+		}},
+		{"WARNING CONDITION: RIBBON IN [0000000000000002]", Alert{
+			Kind: "WARNING CONDITION", Condition: "RIBBON IN", Code: "0000000000000002",
+			Raw: "WARNING CONDITION: RIBBON IN [0000000000000002]",
+		}},
+		{"ALERT: PQ JOB COMPLETED\r\n", Alert{
+			Kind: "ALERT", Condition: "PQ JOB COMPLETED", Raw: "ALERT: PQ JOB COMPLETED",
+		}},
+		{"  warning cleared :  replace head ", Alert{
+			Kind: "WARNING CLEARED", Condition: "replace head", Cleared: true,
+			Raw: "  warning cleared :  replace head ",
+		}},
+	}
+	for _, tt := range tests {
+		got, ok := ParseAlert(tt.line)
+		if !ok {
+			t.Errorf("ParseAlert(%q) is not an alert", tt.line)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAlert(%q) =\n%+v\nwant\n%+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseAlertNotAlert(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"\r\n",
+		hsLine1,
+		"ERROR CONDITION PAPER OUT",
+		"ALERTS: ON",
+		`"E:ARIAL.TTF" 4820`,
+		"PAPER OUT: ERROR CONDITION",
+	} {
+		if a, ok := ParseAlert(line); ok {
+			t.Errorf("ParseAlert(%q) = %+v, want no alert", line, a)
+		}
+	}
+}
+
+// chatTransport is a Transport over which a printer answers ~HS with the
+// reply chunks given, as if they arrived in separate reads.
+type chatTransport struct {
+	reply     []string
+	in        chan string
+	closeOnce sync.Once
+}
+
+func newChatTransport(reply ...string) *chatTransport {
+	return &chatTransport{reply: reply, in: make(chan string, len(reply)+1)}
+}
+
+func (c *chatTransport) Write(b []byte) (int, error) {
+	if strings.Contains(string(b), "~HS") {
+		for _, r := range c.reply {
+			c.in <- r
+		}
+	}
+	return len(b), nil
+}
+
+func (c *chatTransport) Read(b []byte) (int, error) {
+	r, ok := <-c.in
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(b, r), nil
+}
+
+func (c *chatTransport) Close() error {
+	c.closeOnce.Do(func() { close(c.in) })
+	return nil
+}
+
+func TestAlertDuringQuery(t *testing.T) {
+	paperOut := "ERROR CONDITION: PAPER OUT [00000001000000]\r\n"
+	tests := []struct {
+		name  string
+		reply []string
+	}{
+		{"between strings", []string{hsLine1, paperOut, hsLine2 + hsLine3}},
+		{"split alert", []string{hsLine1 + "ERROR COND", "ITION: PAPER OUT", " [00000001000000]\r\n" + hsLine2, hsLine3}},
+		{"first", []string{paperOut + hsLine1 + hsLine2 + hsLine3}},
+	}
+	want := HostStatus{
+		CommSettings: 30, LabelLength: 1245, FunctionSettings: 1,
+		ThermalTransfer: true, PrintMode: 2, PrintWidthMode: 6, Password: 1234,
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewTransportPrinter(newChatTransport(tt.reply...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer p.Close()
+			alerts, err := AlertChannel(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.SetReadDeadline(time.Now().Add(5 * time.Second))
+			got, err := p.Status()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("Status() =\n%+v\nwant\n%+v", got, want)
+			}
+			select {
+			case a := <-alerts:
+				if a.Condition != "PAPER OUT" || a.Code != "00000001000000" {
+					t.Errorf("alert = %+v, want PAPER OUT", a)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no alert received")
+			}
+
+			p.Close()
+			for range alerts {
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package zpl
+
+import "fmt"
+
+// AuxPortMode is how the printer handles label errors on its applicator
+// port, the first ^JJ parameter.
+type AuxPortMode int
+
+// Auxiliary port modes.
+const (
+	AuxPortOff            AuxPortMode = iota // the port is off
+	AuxPortReprintOnError                    // stop on a bad label and reprint it once PAUSE is pressed
+	AuxPortMaintenance                       // do not stop on errors
+)
+
+// EndPrintSignal is how the applicator port's End Print line reports
+// printing, the second ^JJ parameter.
+type EndPrintSignal int
+
+// End Print signal modes. The pulse modes pulse the line for 20 ms once a
+// label is printed and positioned.
+const (
+	EndPrintOff             EndPrintSignal = iota // the signal is off
+	EndPrintLowWhileMoving                        // normally high, low while a label moves forward
+	EndPrintHighWhileMoving                       // normally low, high while a label moves forward
+	EndPrintLowPulse                              // normally high, pulsed low after each label
+	EndPrintHighPulse                             // normally low, pulsed high after each label
+)
+
+// StartPrintSignal is how the applicator's Start Print line triggers a
+// label, the third ^JJ parameter.
+type StartPrintSignal byte
+
+// Start Print signal modes. The zero value leaves the printer default,
+// which is off.
+const (
+	StartPrintPulse StartPrintSignal = 'p' // the line must be released before the next label
+	StartPrintLevel StartPrintSignal = 'l' // labels print for as long as the line is held low
+)
+
+// ApplicatorErrorMode is what the printer does when it loses track of the
+// labels, the fourth ^JJ parameter.
+type ApplicatorErrorMode byte
+
+// Applicator error modes. The zero value leaves the printer default,
+// ApplicatorErrorFeed.
+const (
+	ApplicatorErrorPause ApplicatorErrorMode = 'e' // assert Service Required and pause
+	ApplicatorErrorFeed  ApplicatorErrorMode = 'f' // feed blank labels until the web is found
+)
+
+// ApplicatorConfig is the applicator port setup sent by SetApplicatorMode.
+// The zero value turns the port off and leaves the other settings at their
+// printer defaults.
+type ApplicatorConfig struct {
+	AuxPort    AuxPortMode
+	EndPrint   EndPrintSignal
+	StartPrint StartPrintSignal
+	OnError    ApplicatorErrorMode
+	// Reprint makes the printer reprint the last label when the Reprint
+	// line is asserted. The label is kept in memory until then.
+	Reprint bool
+	// NoRibbonLowWarning stops the Ribbon Low line from being asserted
+	// when the ribbon runs low.
+	NoRibbonLowWarning bool
+}
+
+// SetApplicatorMode configures the applicator port of a print and apply
+// printer with ^JJ, checking every setting before anything is sent. It is
+// used together with PrintModeApplicator and needs the applicator option
+// installed; the printer keeps the setup until it is changed.
+func SetApplicatorMode(p PrinterConnection, cfg ApplicatorConfig) error {
+	cmd, err := cfg.command()
+	if err != nil {
+		return err
+	}
+	return p.SendZPL("^XA" + cmd + "^XZ")
+}
+
+// command returns the ^JJ command for c.
+func (c ApplicatorConfig) command() (string, error) {
+	if c.AuxPort < AuxPortOff || c.AuxPort > AuxPortMaintenance {
+		return "", fmt.Errorf("invalid auxiliary port mode %d: must be between %d and %d", c.AuxPort, AuxPortOff, AuxPortMaintenance)
+	}
+	if c.EndPrint < EndPrintOff || c.EndPrint > EndPrintHighPulse {
+		return "", fmt.Errorf("invalid end print signal %d: must be between %d and %d", c.EndPrint, EndPrintOff, EndPrintHighPulse)
+	}
+	var start, onError string
+	switch c.StartPrint {
+	case 0:
+	case StartPrintPulse, StartPrintLevel:
+		start = string(rune(c.StartPrint))
+	default:
+		return "", fmt.Errorf("invalid start print signal %q: must be p or l", rune(c.StartPrint))
+	}
+	switch c.OnError {
+	case 0:
+	case ApplicatorErrorPause, ApplicatorErrorFeed:
+		onError = string(rune(c.OnError))
+	default:
+		return "", fmt.Errorf("invalid applicator error mode %q: must be e or f", rune(c.OnError))
+	}
+	reprint, ribbonLow := 'd', 'e'
+	if c.Reprint {
+		reprint = 'e'
+	}
+	if c.NoRibbonLowWarning {
+		ribbonLow = 'd'
+	}
+	return fmt.Sprintf("^JJ%d,%d,%s,%s,%c,%c", c.AuxPort, c.EndPrint, start, onError, reprint, ribbonLow), nil
+}
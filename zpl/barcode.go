@@ -0,0 +1,107 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// defaultBarHeight is the height in dots of linear barcodes and
+// defaultModuleSize the element size of Data Matrix symbols, unless
+// overridden with WithBarHeight.
+const (
+	defaultBarHeight  = 100
+	defaultModuleSize = 10
+)
+
+// Orientation is the rotation of a field.
+type Orientation byte
+
+// Field orientations, clockwise.
+const (
+	Normal     Orientation = 'N'
+	Rotated90  Orientation = 'R'
+	Inverted   Orientation = 'I'
+	Rotated270 Orientation = 'B'
+)
+
+// BarcodeOption overrides defaults of the barcode builder methods.
+type BarcodeOption func(*barcodeOptions)
+
+type barcodeOptions struct {
+	orientation Orientation
+	height      int
+}
+
+func newBarcodeOptions(height int, opts []BarcodeOption) barcodeOptions {
+	o := barcodeOptions{orientation: Normal, height: height}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithOrientation rotates the barcode.
+func WithOrientation(o Orientation) BarcodeOption {
+	return func(bo *barcodeOptions) {
+		bo.orientation = o
+	}
+}
+
+// WithBarHeight sets the bar height of linear barcodes, or the module size
+// of Data Matrix symbols, in dots.
+func WithBarHeight(dots int) BarcodeOption {
+	return func(bo *barcodeOptions) {
+		bo.height = dots
+	}
+}
+
+// Code128 adds a Code 128 barcode (^BC) at (x, y) with the interpretation
+// line printed below it.
+func (b *LabelBuilder) Code128(x, y int, data string, opts ...BarcodeOption) *LabelBuilder {
+	o := newBarcodeOptions(defaultBarHeight, opts)
+	return b.barcode(x, y, data, fmt.Sprintf("^BC%c,%d,Y,N,N", o.orientation, o.height))
+}
+
+// Code39 adds a Code 39 barcode (^B3) at (x, y) with the interpretation line
+// printed below it.
+func (b *LabelBuilder) Code39(x, y int, data string, opts ...BarcodeOption) *LabelBuilder {
+	o := newBarcodeOptions(defaultBarHeight, opts)
+	return b.barcode(x, y, data, fmt.Sprintf("^B3%c,N,%d,Y,N", o.orientation, o.height))
+}
+
+// QRCode adds a QR code (^BQ, model 2) at (x, y). magnification is the size
+// of each module in dots, 1 to 10. Data is encoded with error correction
+// level Q and automatic input mode. QR codes are always printed upright.
+func (b *LabelBuilder) QRCode(x, y int, data string, magnification int) *LabelBuilder {
+	if magnification < 1 || magnification > 10 {
+		b.fail(fmt.Errorf("invalid QR magnification %d: must be between 1 and 10", magnification))
+		return b
+	}
+	if data == "" {
+		b.fail(errEmptyBarcode)
+		return b
+	}
+	return b.barcode(x, y, "QA,"+data, fmt.Sprintf("^BQN,2,%d", magnification))
+}
+
+// DataMatrix adds an ECC 200 Data Matrix symbol (^BX) at (x, y).
+// WithBarHeight sets the module size.
+func (b *LabelBuilder) DataMatrix(x, y int, data string, opts ...BarcodeOption) *LabelBuilder {
+	o := newBarcodeOptions(defaultModuleSize, opts)
+	return b.barcode(x, y, data, fmt.Sprintf("^BX%c,%d,200", o.orientation, o.height))
+}
+
+var errEmptyBarcode = errors.New("barcode data must not be empty")
+
+// barcode writes a barcode field made of the symbology command cmd and data.
+func (b *LabelBuilder) barcode(x, y int, data, cmd string) *LabelBuilder {
+	if data == "" {
+		b.fail(errEmptyBarcode)
+		return b
+	}
+	if !b.field(x, y) {
+		return b
+	}
+	b.body.WriteString(cmd)
+	return b.Data(data)
+}
@@ -0,0 +1,113 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// BatchError reports which label of a SendBatch call failed.
+type BatchError struct {
+	Index int // index in labels of the first label that may not have been sent
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch label %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// SendBatch sends labels, each a complete ^XA/^XZ format, as one newline
+// separated stream instead of one send per label. Every label is checked
+// with ValidateZPL before anything is sent.
+//
+// When p is one of the printers of this package, or wraps one, each label
+// is prepared as SendZPL would prepare it: WithProlog, WithEncoding and
+// WithStrictValidation apply to every label, unlike to SendZPLReader. The
+// options of other connections are not known to SendBatch, which sends
+// their labels as given.
+//
+// The stream goes through SendZPLReader, so it is chunked like any stream
+// and not retried on reconnect. On failure the returned *BatchError holds
+// the index of the first label that may not have reached the printer;
+// earlier ones did.
+func SendBatch(p PrinterConnection, labels []string) error {
+	prep, _ := asPreparer(p)
+	prepared := make([]string, len(labels))
+	for i, label := range labels {
+		if strings.TrimSpace(label) == "" {
+			return &BatchError{i, errors.New("label is empty")}
+		}
+		if err := ValidateZPL(label); err != nil {
+			return &BatchError{i, err}
+		}
+		prepared[i] = label
+		if prep != nil {
+			var err error
+			if prepared[i], err = prep.prepare(label); err != nil {
+				return &BatchError{i, err}
+			}
+		}
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	r := &batchReader{labels: prepared}
+	if err := p.SendZPLReader(r); err != nil {
+		return &BatchError{r.chunkStart, err}
+	}
+	return nil
+}
+
+// preparer is implemented by printers that transform every ZPL payload
+// they send, as configured by their options.
+type preparer interface {
+	prepare(zpl string) (string, error)
+}
+
+// asPreparer finds the printer beneath any decorators around p that
+// prepares its payloads.
+func asPreparer(p PrinterConnection) (preparer, bool) {
+	for {
+		if r, ok := p.(preparer); ok {
+			return r, true
+		}
+		w, ok := p.(wrapper)
+		if !ok {
+			return nil, false
+		}
+		p = w.unwrap()
+	}
+}
+
+// batchReader reads labels one after the other, each terminated by a
+// newline, and remembers which label the latest read began in.
+type batchReader struct {
+	labels     []string
+	i          int // current label
+	off        int // offset in the current label
+	chunkStart int // label the latest non-empty read began in
+}
+
+func (r *batchReader) Read(b []byte) (int, error) {
+	if r.i >= len(r.labels) {
+		return 0, io.EOF
+	}
+	r.chunkStart = r.i
+	n := 0
+	for n < len(b) && r.i < len(r.labels) {
+		label := withNewline(r.labels[r.i])
+		c := copy(b[n:], label[r.off:])
+		n += c
+		r.off += c
+		if r.off == len(label) {
+			r.i++
+			r.off = 0
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,37 @@
+package zpl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSendBatchPreparesLabels(t *testing.T) {
+	wire := new(bufTransport)
+	p, err := NewTransportPrinter(wire, WithProlog("^PW812"), WithEncoding(EncodingLatin1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SendBatch(p, []string{"^XA^FDé^FS^XZ", "^XA^FDb^FS^XZ\n"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "^XA^CI27^PW812^FD\xe9^FS^XZ\n^XA^CI27^PW812^FDb^FS^XZ\n"
+	if got := wire.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSendBatchPrepareError(t *testing.T) {
+	wire := new(bufTransport)
+	p, err := NewTransportPrinter(wire, WithEncoding(EncodingLatin1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SendBatch(p, []string{"^XA^FDa^FS^XZ", "^XA^FD☃^FS^XZ"})
+	var be *BatchError
+	if !errors.As(err, &be) || be.Index != 1 {
+		t.Fatalf("got %v, want a BatchError for label 1", err)
+	}
+	if wire.Len() != 0 {
+		t.Errorf("sent %q before the batch was checked", wire.String())
+	}
+}
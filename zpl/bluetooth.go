@@ -0,0 +1,65 @@
+package zpl
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// DefaultBluetoothTimeout bounds connecting to a BluetoothPrinter and each
+// of its writes.
+const DefaultBluetoothTimeout = 15 * time.Second
+
+// maxRFCOMMChannel is the highest RFCOMM channel number.
+const maxRFCOMMChannel = 30
+
+// BluetoothPrinter is a mobile printer, such as the ZQ series, reached over
+// the Bluetooth Serial Port Profile through an RFCOMM socket. It is a
+// NetworkPrinter over that socket, including reconnecting with
+// WithReconnect, and is safe for concurrent use.
+//
+// RFCOMM sockets are only available on Linux. On other systems, pair the
+// printer and open the serial port the system creates for it with
+// NewSerialPrinter.
+type BluetoothPrinter struct {
+	*NetworkPrinter
+}
+
+// NewBluetoothPrinter connects to the printer with Bluetooth address mac,
+// such as "AC:3F:A4:12:34:56", on RFCOMM channel, usually 1 for Zebra
+// printers. The printer must already be paired with this host; if it is
+// not, or cannot be reached, the error wraps ErrNotPaired.
+func NewBluetoothPrinter(mac string, channel int, opts ...Option) (*BluetoothPrinter, error) {
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) != 6 {
+		return nil, fmt.Errorf("invalid bluetooth address %q", mac)
+	}
+	if channel < 1 || channel > maxRFCOMMChannel {
+		return nil, fmt.Errorf("invalid RFCOMM channel %d: must be between 1 and %d", channel, maxRFCOMMChannel)
+	}
+	addr := fmt.Sprintf("%s/%d", hw, channel)
+	o := newOptions(opts).with("transport", "bluetooth", "addr", addr)
+	conn, err := dialNetworkPrinter(addr, DefaultBluetoothTimeout, o, func(timeout time.Duration) (net.Conn, error) {
+		return dialRFCOMM(hw, channel, timeout)
+	})
+	if err != nil {
+		return nil, err
+	}
+	p := &BluetoothPrinter{NetworkPrinter: conn}
+	if err := o.connected(p); err != nil {
+		p.Close()
+		return nil, err
+	}
+	p.startHeartbeat()
+	return p, nil
+}
+
+// rfcommAddr is the net.Addr of an RFCOMM connection.
+type rfcommAddr struct {
+	mac     net.HardwareAddr
+	channel int
+}
+
+func (a rfcommAddr) Network() string { return "rfcomm" }
+
+func (a rfcommAddr) String() string { return fmt.Sprintf("%s/%d", a.mac, a.channel) }
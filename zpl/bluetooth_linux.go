@@ -0,0 +1,106 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// rfcommConn is an RFCOMM socket. The socket is non-blocking, so the
+// *os.File supports deadlines like a net.Conn.
+type rfcommConn struct {
+	*os.File
+	remote rfcommAddr
+}
+
+func (c *rfcommConn) LocalAddr() net.Addr  { return rfcommAddr{} }
+func (c *rfcommConn) RemoteAddr() net.Addr { return c.remote }
+
+// dialRFCOMM connects to channel on the device with address mac, giving up
+// after timeout unless it is zero.
+func dialRFCOMM(mac net.HardwareAddr, channel int, timeout time.Duration) (net.Conn, error) {
+	fd, err := unix.Socket(unix.AF_BLUETOOTH, unix.SOCK_STREAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, unix.BTPROTO_RFCOMM)
+	if err != nil {
+		if errors.Is(err, unix.EAFNOSUPPORT) {
+			return nil, fmt.Errorf("bluetooth is not available on this system: %w", err)
+		}
+		return nil, err
+	}
+
+	// bdaddr_t holds the address in little-endian byte order
+	sa := &unix.SockaddrRFCOMM{Channel: uint8(channel)}
+	copy(sa.Addr[:], mac)
+	slices.Reverse(sa.Addr[:])
+	if err := unix.Connect(fd, sa); err != nil && !errors.Is(err, unix.EINPROGRESS) {
+		unix.Close(fd)
+		return nil, rfcommError(err, channel)
+	}
+
+	f := os.NewFile(uintptr(fd), "rfcomm:"+mac.String())
+	if timeout > 0 {
+		f.SetWriteDeadline(time.Now().Add(timeout))
+	}
+	if err := awaitConnect(f); err != nil {
+		f.Close()
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return nil, err
+		}
+		return nil, rfcommError(err, channel)
+	}
+	f.SetWriteDeadline(time.Time{})
+	return &rfcommConn{File: f, remote: rfcommAddr{mac: mac, channel: channel}}, nil
+}
+
+// awaitConnect waits until the non-blocking connect on f completes and
+// returns its outcome.
+func awaitConnect(f *os.File) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var connErr error
+	first := true
+	werr := rc.Write(func(fd uintptr) bool {
+		// The socket becomes writable once the connect completes
+		if first {
+			first = false
+			return false
+		}
+		v, err := unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_ERROR)
+		if err != nil {
+			connErr = err
+			return true
+		}
+		switch errno := unix.Errno(v); errno {
+		case 0:
+			return true
+		case unix.EINPROGRESS, unix.EALREADY, unix.EINTR:
+			return false
+		default:
+			connErr = errno
+			return true
+		}
+	})
+	if werr != nil {
+		return werr
+	}
+	return connErr
+}
+
+// rfcommError explains a failed RFCOMM connect.
+func rfcommError(err error, channel int) error {
+	switch {
+	case errors.Is(err, unix.EHOSTDOWN), errors.Is(err, unix.EHOSTUNREACH):
+		return fmt.Errorf("%w: check that it is paired, switched on and in range: %w", ErrNotPaired, err)
+	case errors.Is(err, unix.EACCES), errors.Is(err, unix.EPERM), errors.Is(err, unix.ECONNRESET):
+		return fmt.Errorf("%w: the printer rejected authentication; pair it first, for example with bluetoothctl: %w", ErrNotPaired, err)
+	case errors.Is(err, unix.ECONNREFUSED):
+		return fmt.Errorf("no serial port service on RFCOMM channel %d: %w", channel, err)
+	}
+	return err
+}
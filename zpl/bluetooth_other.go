@@ -0,0 +1,15 @@
+//go:build !linux
+
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// dialRFCOMM fails: RFCOMM sockets are only supported on Linux.
+func dialRFCOMM(mac net.HardwareAddr, channel int, timeout time.Duration) (net.Conn, error) {
+	return nil, fmt.Errorf("%w: RFCOMM sockets are only supported on Linux; open the printer's Bluetooth serial port with NewSerialPrinter", errors.ErrUnsupported)
+}
@@ -0,0 +1,22 @@
+package zpl
+
+import "sync"
+
+// Broadcast sends zpl to every printer at once and returns their errors in
+// the same order, nil for each printer that took the label. Each send runs
+// in its own goroutine, so a slow or failed printer does not hold up the
+// others; Broadcast returns once all of them are done. Use errors.Join on
+// the result for a single error.
+func Broadcast(printers []PrinterConnection, zpl string) []error {
+	errs := make([]error, len(printers))
+	var wg sync.WaitGroup
+	for i, p := range printers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = p.SendZPL(zpl)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,128 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// defaultBufferSize is the BufferedPrinter buffer size when none is given.
+const defaultBufferSize = 16 * 1024
+
+// BufferedPrinter coalesces many small sends into fewer, larger ones to the
+// printer it wraps. SendZPL and its variants only append to the buffer;
+// it is sent once it reaches its size, on Flush, or ahead of a raw send or
+// stream so that the order of the data is kept. It is safe for concurrent
+// use.
+//
+// Buffered data is sent at size boundaries, not format boundaries, so the
+// wrapped printer should not be strict. Queries such as Status go straight
+// to the wrapped printer; Flush first if they must see the buffered labels.
+type BufferedPrinter struct {
+	PrinterConnection
+
+	mu   sync.Mutex
+	buf  []byte
+	size int
+}
+
+// NewBufferedPrinter wraps p with a buffer of size bytes, or 16 KB if size
+// is 0 or less.
+func NewBufferedPrinter(p PrinterConnection, size int) *BufferedPrinter {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	return &BufferedPrinter{PrinterConnection: p, buf: make([]byte, 0, size), size: size}
+}
+
+// SendZPL buffers zpl, with a trailing newline added if missing.
+func (b *BufferedPrinter) SendZPL(zpl string) error {
+	return b.SendZPLContext(context.Background(), zpl)
+}
+
+// SendZPLN is like SendZPL but also returns the number of bytes buffered,
+// including the added newline.
+func (b *BufferedPrinter) SendZPLN(zpl string) (int, error) {
+	zpl = withNewline(zpl)
+	if err := b.SendZPL(zpl); err != nil {
+		return 0, err
+	}
+	return len(zpl), nil
+}
+
+// SendZPLContext is like SendZPL; ctx bounds the send when the buffer
+// fills.
+func (b *BufferedPrinter) SendZPLContext(ctx context.Context, zpl string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, withNewline(zpl)...)
+	if len(b.buf) < b.size {
+		return nil
+	}
+	return b.flush(ctx)
+}
+
+// SendZPLReader flushes the buffer and then streams r to the printer.
+func (b *BufferedPrinter) SendZPLReader(r io.Reader) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flush(context.Background()); err != nil {
+		return err
+	}
+	return b.PrinterConnection.SendZPLReader(r)
+}
+
+// SendRaw flushes the buffer and then writes data unchanged.
+func (b *BufferedPrinter) SendRaw(data []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.flush(context.Background()); err != nil {
+		return 0, err
+	}
+	return b.PrinterConnection.SendRaw(data)
+}
+
+// RawSend is SendRaw without the byte count.
+func (b *BufferedPrinter) RawSend(data []byte) error {
+	_, err := b.SendRaw(data)
+	return err
+}
+
+// Buffered returns the number of bytes waiting in the buffer.
+func (b *BufferedPrinter) Buffered() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.buf)
+}
+
+// Flush sends the buffered data. If the send fails the data is discarded
+// and the error returned.
+func (b *BufferedPrinter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flush(context.Background())
+}
+
+// flush implements Flush. The caller holds b.mu.
+func (b *BufferedPrinter) flush(ctx context.Context) error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	data := string(b.buf)
+	b.buf = b.buf[:0]
+	return b.PrinterConnection.SendZPLContext(ctx, data)
+}
+
+// Close flushes the buffer and closes the wrapped printer, even if the
+// flush failed.
+func (b *BufferedPrinter) Close() error {
+	return errors.Join(b.Flush(), b.PrinterConnection.Close())
+}
+
+func (b *BufferedPrinter) unwrap() PrinterConnection {
+	return b.PrinterConnection
+}
@@ -0,0 +1,86 @@
+package zpl
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Years the printer's real-time clock can hold.
+const (
+	minClockYear = 1998
+	maxClockYear = 2097
+)
+
+// SetClock sets the real-time clock of a printer with the RTC option to
+// the wall clock time of t, in t's location, with ^ST. The printer keeps
+// no time zone.
+func SetClock(p PrinterConnection, t time.Time) error {
+	if y := t.Year(); y < minClockYear || y > maxClockYear {
+		return fmt.Errorf("invalid clock year %d: must be between %d and %d", y, minClockYear, maxClockYear)
+	}
+	return p.SendZPL(fmt.Sprintf("^XA^ST%02d,%02d,%04d,%02d,%02d,%02d,M^XZ",
+		t.Month(), t.Day(), t.Year(), t.Hour(), t.Minute(), t.Second()))
+}
+
+// GetClock reads the printer's real-time clock from the rtc.date and
+// rtc.time settings, taking its wall clock time to be in time.Local. It
+// needs a bidirectional connection.
+func GetClock(p PrinterConnection) (time.Time, error) {
+	date, err := GetVar(p, "rtc.date")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read clock date: %w", err)
+	}
+	clock, err := GetVar(p, "rtc.time")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read clock time: %w", err)
+	}
+	return parseClock(date, clock, time.Local)
+}
+
+// clockDateLayouts are the rtc.date formats seen on printers: month first,
+// as ^ST takes it, and ISO order.
+var clockDateLayouts = []string{"01-02-2006", "01/02/2006", "2006-01-02", "01-02-06", "01/02/06"}
+
+// clockTimeLayouts are the rtc.time formats, in 24 and 12 hour mode.
+var clockTimeLayouts = []string{"15:04:05", "15:04", "03:04:05PM", "03:04:05 PM", "03:04PM", "03:04 PM"}
+
+// parseClock combines the rtc.date and rtc.time values into a time in loc.
+func parseClock(date, clock string, loc *time.Location) (time.Time, error) {
+	date, clock = strings.TrimSpace(date), strings.ToUpper(strings.TrimSpace(clock))
+	var d, c time.Time
+	var err error
+	for _, layout := range clockDateLayouts {
+		if d, err = time.Parse(layout, date); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: clock date %q", ErrMalformedResponse, date)
+	}
+	for _, layout := range clockTimeLayouts {
+		if c, err = time.Parse(layout, clock); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: clock time %q", ErrMalformedResponse, clock)
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), c.Hour(), c.Minute(), c.Second(), 0, loc), nil
+}
+
+// ClockField adds a field at (x, y) that prints the printer's real-time
+// clock in the current font, with format made of ^FC clock codes such as
+// "%m/%d/%Y %H:%M" and literal text. The clock is read when the label
+// prints, so it needs the RTC option and a clock set with SetClock.
+func (b *LabelBuilder) ClockField(x, y int, format string) *LabelBuilder {
+	if !strings.Contains(format, "%") {
+		b.fail(fmt.Errorf("invalid clock format %q: must contain a %% clock code", format))
+		return b
+	}
+	if !b.field(x, y) {
+		return b
+	}
+	b.body.WriteString("^FC%")
+	return b.Data(format)
+}
@@ -0,0 +1,52 @@
+package zpl
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseClock(t *testing.T) {
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	tests := []struct {
+		date, clock string
+		want        time.Time
+	}{
+		{"03-15-2024", "14:05:09", time.Date(2024, 3, 15, 14, 5, 9, 0, loc)},
+		{"03/15/2024", "14:05", time.Date(2024, 3, 15, 14, 5, 0, 0, loc)},
+		{"2024-03-15", "02:05:09PM", time.Date(2024, 3, 15, 14, 5, 9, 0, loc)},
+		{"03-15-24", "02:05:09 am", time.Date(2024, 3, 15, 2, 5, 9, 0, loc)},
+		{"12/31/97", "12:00 AM", time.Date(1997, 12, 31, 0, 0, 0, 0, loc)},
+		{" 01-01-2000\r\n", " 23:59:59 ", time.Date(2000, 1, 1, 23, 59, 59, 0, loc)},
+		{"02-29-2024", "12:30PM", time.Date(2024, 2, 29, 12, 30, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		got, err := parseClock(tt.date, tt.clock, loc)
+		if err != nil {
+			t.Errorf("parseClock(%q, %q): %v", tt.date, tt.clock, err)
+			continue
+		}
+		if !got.Equal(tt.want) || got.Location() != loc {
+			t.Errorf("parseClock(%q, %q) = %v, want %v", tt.date, tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestParseClockMalformed(t *testing.T) {
+	tests := []struct {
+		date, clock string
+	}{
+		{"", "14:05:09"},
+		{"03-15-2024", ""},
+		{"15-03-2024", "14:05:09"},
+		{"02-30-2024", "14:05:09"},
+		{"03-15-2024", "25:00:00"},
+		{"03-15-2024", "14.05.09"},
+		{"?", "?"},
+	}
+	for _, tt := range tests {
+		if _, err := parseClock(tt.date, tt.clock, time.UTC); !errors.Is(err, ErrMalformedResponse) {
+			t.Errorf("parseClock(%q, %q) = %v, want ErrMalformedResponse", tt.date, tt.clock, err)
+		}
+	}
+}
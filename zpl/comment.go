@@ -0,0 +1,36 @@
+package zpl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Comment adds a ^FX comment, terminated with ^FS, to the current format.
+// The printer ignores comments, so they suit metadata such as a product
+// line or revision, read back with ExtractComments. The comment ends at the
+// next caret, and a tilde would start a control command, so text must
+// contain neither.
+func (b *LabelBuilder) Comment(text string) *LabelBuilder {
+	if strings.ContainsAny(text, "^~") {
+		b.fail(fmt.Errorf("invalid comment %q: must not contain ^ or ~", text))
+		return b
+	}
+	b.ensureOpen()
+	b.closeField()
+	fmt.Fprintf(&b.body, "^FX%s^FS\n", text)
+	return b
+}
+
+// ExtractComments returns the text of every ^FX comment in zpl, in order
+// and with surrounding whitespace trimmed. A comment runs up to the next
+// caret, usually that of its ^FS. Commands are found as ParseZPL finds
+// them, so a prefix changed with ^CC is followed.
+func ExtractComments(zpl string) []string {
+	var comments []string
+	for _, c := range ParseZPL(zpl) {
+		if c.Prefix == '^' && c.Code == "FX" {
+			comments = append(comments, strings.TrimSpace(c.Data))
+		}
+	}
+	return comments
+}
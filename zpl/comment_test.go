@@ -0,0 +1,32 @@
+package zpl
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractComments(t *testing.T) {
+	tests := []struct {
+		name string
+		zpl  string
+		want []string
+	}{
+		{"none", "^XA^FO10,10^FDHello^FS^XZ", nil},
+		{"built", NewLabel().Comment("SKU 4711").Comment(" rev 3 ").String(), []string{"SKU 4711", "rev 3"}},
+		{"designer export", "^XA\n^FX Shipping label v2 ^FS\n^FO50,50^A0N,40,40^FDShip to^FS\n^FX -- address block --\n^FO50,100^FDJane Doe^FS\n^XZ",
+			[]string{"Shipping label v2", "-- address block --"}},
+		{"lower case", "^xa^fxpart 9^fs^xz", []string{"part 9"}},
+		{"unterminated at the end", "^XA^XZ^FX trailing", []string{"trailing"}},
+		{"tilde in a comment", "^XA^FX~HS is literal here^FS^XZ", []string{"~HS is literal here"}},
+		{"prefix changed", "^XA^CC!!FXafter ^CC!FS!XZ", []string{"after ^CC"}},
+		{"old prefix after ^CC", "^XA^CC!^FXnot a comment!FS!XZ", nil},
+		{"graphic data", "~DGR:A.GRF,2,1,5E46\n^XA^FXlogo^FS^XZ", []string{"logo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractComments(tt.zpl); !slices.Equal(got, tt.want) {
+				t.Errorf("ExtractComments(%q) = %q, want %q", tt.zpl, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Printer connection types accepted in Config.Type.
+const (
+	TypeUSB     = "usb"
+	TypeNetwork = "network"
+	TypeSerial  = "serial"
+)
+
+// DefaultBaudRate is used for serial printers when Config.Baud is zero.
+const DefaultBaudRate = 9600
+
+// ErrInvalidConfig is returned by NewPrinter for incomplete or
+// contradictory configurations.
+var ErrInvalidConfig = errors.New("invalid printer config")
+
+// Config describes a printer connection, so it can be loaded from a file or
+// the environment and opened with NewPrinter.
+type Config struct {
+	Type string `json:"type" yaml:"type"`
+
+	// Network
+	Address string        `json:"address,omitempty" yaml:"address,omitempty"`
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// USB; both zero means the TLP 2844 defaults
+	VendorID  uint16 `json:"vendor_id,omitempty" yaml:"vendor_id,omitempty"`
+	ProductID uint16 `json:"product_id,omitempty" yaml:"product_id,omitempty"`
+
+	// Serial
+	Port string `json:"port,omitempty" yaml:"port,omitempty"`
+	Baud int    `json:"baud,omitempty" yaml:"baud,omitempty"`
+}
+
+// NewPrinter opens the printer described by cfg.
+func NewPrinter(cfg Config, opts ...Option) (PrinterConnection, error) {
+	var (
+		p   PrinterConnection
+		err error
+	)
+	switch cfg.Type {
+	case TypeUSB:
+		if (cfg.VendorID == 0) != (cfg.ProductID == 0) {
+			return nil, fmt.Errorf("%w: usb needs both vendor_id and product_id, or neither", ErrInvalidConfig)
+		}
+		vid, pid := cfg.VendorID, cfg.ProductID
+		if vid == 0 {
+			vid, pid = usbVendorID, usbProductID
+		}
+		p, err = nilIfErr(NewUSBPrinterWithID(vid, pid, opts...))
+	case TypeNetwork:
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("%w: network needs an address", ErrInvalidConfig)
+		}
+		timeout := cfg.Timeout
+		if timeout == 0 {
+			timeout = DefaultNetworkTimeout
+		}
+		p, err = nilIfErr(NewNetworkPrinterWithTimeout(cfg.Address, timeout, opts...))
+	case TypeSerial:
+		if cfg.Port == "" {
+			return nil, fmt.Errorf("%w: serial needs a port", ErrInvalidConfig)
+		}
+		baud := cfg.Baud
+		if baud == 0 {
+			baud = DefaultBaudRate
+		}
+		p, err = nilIfErr(NewSerialPrinter(cfg.Port, baud, opts...))
+	case "":
+		return nil, fmt.Errorf("%w: missing type", ErrInvalidConfig)
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidConfig, cfg.Type)
+	}
+	return p, err
+}
+
+// nilIfErr converts a constructor result to a PrinterConnection, keeping a
+// failed constructor's nil pointer from becoming a non-nil interface.
+func nilIfErr[P PrinterConnection](p P, err error) (PrinterConnection, error) {
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
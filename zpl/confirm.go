@@ -0,0 +1,93 @@
+package zpl
+
+import (
+	"fmt"
+	"time"
+)
+
+// confirmPollInterval is the pause between status checks in SendAndConfirm.
+const confirmPollInterval = 250 * time.Millisecond
+
+// PausedAction is what SendWhenReady does when the printer is paused.
+type PausedAction int
+
+const (
+	// FailIfPaused returns an error wrapping ErrPrinterPaused without
+	// sending anything.
+	FailIfPaused PausedAction = iota
+	// ResumeIfPaused resumes the printer with ~PS and then sends.
+	ResumeIfPaused
+)
+
+// SendWhenReady checks the printer with ~HS before sending zpl, so labels
+// do not pile up unseen in a paused printer. A paused printer is handled
+// as onPause says; one reporting a fault fails the call with an error
+// wrapping ErrPrinterFault, and nothing is sent. p must be able to read
+// responses back.
+func SendWhenReady(p PrinterConnection, zpl string, onPause PausedAction) error {
+	if onPause != FailIfPaused && onPause != ResumeIfPaused {
+		return fmt.Errorf("invalid paused action %d", onPause)
+	}
+	s, err := hostStatus(p)
+	if err != nil {
+		return fmt.Errorf("failed to read printer status: %w", err)
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("printer not ready: %w", err)
+	}
+	if s.Paused {
+		if onPause == FailIfPaused {
+			return fmt.Errorf("printer not ready: %w with %d formats in buffer", ErrPrinterPaused, s.FormatsInBuffer)
+		}
+		if err := Resume(p); err != nil {
+			return fmt.Errorf("failed to resume printer: %w", err)
+		}
+	}
+	return p.SendZPL(zpl)
+}
+
+// SendAndConfirm sends zpl and waits up to timeout for the printer to work
+// through it, polling ~HS. It succeeds once the receive buffer is empty and
+// no labels of the batch remain, and fails as soon as the printer reports
+// a fault (wrapping ErrPrinterFault) or is paused (ErrPrinterPaused).
+// Faults present before sending fail the call without sending anything.
+//
+// ~HS has no label counter, so success means the printer finished the
+// format without reporting a fault, not that someone took the label. p
+// must be able to read responses back.
+func SendAndConfirm(p PrinterConnection, zpl string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	s, err := hostStatus(p)
+	if err != nil {
+		return fmt.Errorf("failed to read printer status: %w", err)
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("printer not ready: %w", err)
+	}
+	if err := p.SendZPL(zpl); err != nil {
+		return err
+	}
+
+	for {
+		wait := min(responseTimeout, time.Until(deadline))
+		raw, err := query(p, "~HS", frames(3), max(wait, confirmPollInterval))
+		if err != nil {
+			return fmt.Errorf("failed to read printer status: %w", err)
+		}
+		if s, err = ParseHostStatus(raw); err != nil {
+			return err
+		}
+		switch {
+		case s.Err() != nil:
+			return fmt.Errorf("label not printed: %w", s.Err())
+		case s.Paused:
+			return fmt.Errorf("label not printed: %w with %d formats in buffer", ErrPrinterPaused, s.FormatsInBuffer)
+		case s.FormatsInBuffer == 0 && s.LabelsRemaining == 0 && !s.PartialFormat:
+			return nil
+		}
+		if time.Now().Add(confirmPollInterval).After(deadline) {
+			return fmt.Errorf("label not confirmed within %s: %d formats in buffer, %d labels remaining", timeout, s.FormatsInBuffer, s.LabelsRemaining)
+		}
+		time.Sleep(confirmPollInterval)
+	}
+}
@@ -0,0 +1,58 @@
+package zpl
+
+import "fmt"
+
+// CancelAll sends ~JA, which cancels every format in the printer's buffer,
+// including the label being printed.
+func CancelAll(p PrinterConnection) error {
+	return p.SendZPL("~JA")
+}
+
+// Pause sends ~PP, which stops printing once the current label is done.
+// Queued formats stay in the buffer until Resume.
+func Pause(p PrinterConnection) error {
+	return p.SendZPL("~PP")
+}
+
+// Resume sends ~PS, which restarts a printer paused by Pause or by the
+// front panel.
+func Resume(p PrinterConnection) error {
+	return p.SendZPL("~PS")
+}
+
+// Calibrate sends ~JC, which feeds labels to recalibrate the media and
+// ribbon sensors, as the panel does after a new roll is loaded.
+func Calibrate(p PrinterConnection) error {
+	return p.SendZPL("~JC")
+}
+
+// CalibrateMedia sends ~JG, which calibrates the sensors and prints a graph
+// of their readings, for diagnosing media that is not detected reliably.
+func CalibrateMedia(p PrinterConnection) error {
+	return p.SendZPL("~JG")
+}
+
+// Feed sends ~PH, which advances the media to the start of the next label,
+// like the panel's FEED button.
+func Feed(p PrinterConnection) error {
+	return p.SendZPL("~PH")
+}
+
+// PrintTestPattern prints a solid black bar across the full print width,
+// in which every burnt-out printhead dot shows as a white streak. ZPL has
+// no command for the printer's own PAUSE self test, so the pattern is sent
+// as a label. width is the print width in dots; 0 reads it from the
+// printer's ezpl.print_width setting, which needs a bidirectional
+// connection.
+func PrintTestPattern(p PrinterConnection, width int) error {
+	if width < 0 {
+		return fmt.Errorf("invalid print width %d: must be positive", width)
+	}
+	if width == 0 {
+		var err error
+		if width, err = printWidth(p); err != nil {
+			return err
+		}
+	}
+	return p.SendZPL(fmt.Sprintf("^XA^PW%d^LH0,0\n^FO0,0^GB%d,200,200^FS\n^FO20,220^A0N,30,30^FDPRINTHEAD TEST^FS\n^XZ", width, width))
+}
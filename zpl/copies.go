@@ -0,0 +1,47 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxQuantity is the largest print quantity ^PQ accepts.
+const maxQuantity = 99999999
+
+// SendZPLCopies sends zpl to p with ^PQn inserted before every ^XZ, so the
+// printer prints n copies of each format from a single transfer.
+func SendZPLCopies(p PrinterConnection, zpl string, n int) error {
+	if err := checkQuantity(n); err != nil {
+		return err
+	}
+	withQty, err := insertQuantity(zpl, n)
+	if err != nil {
+		return err
+	}
+	return p.SendZPL(withQty)
+}
+
+// insertQuantity adds ^PQn in front of each ^XZ in zpl.
+func insertQuantity(zpl string, n int) (string, error) {
+	var s strings.Builder
+	last, found := 0, false
+	for i := indexFold(zpl, "^XZ", 0); i >= 0; i = indexFold(zpl, "^XZ", last) {
+		s.WriteString(zpl[last:i])
+		fmt.Fprintf(&s, "^PQ%d", n)
+		s.WriteString(zpl[i : i+3])
+		last, found = i+3, true
+	}
+	if !found {
+		return "", errors.New("no ^XZ to place ^PQ before")
+	}
+	s.WriteString(zpl[last:])
+	return s.String(), nil
+}
+
+func checkQuantity(n int) error {
+	if n < 1 || n > maxQuantity {
+		return fmt.Errorf("invalid print quantity %d: must be between 1 and %d", n, maxQuantity)
+	}
+	return nil
+}
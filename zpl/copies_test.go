@@ -0,0 +1,42 @@
+package zpl
+
+import "testing"
+
+func TestInsertQuantity(t *testing.T) {
+	tests := []struct {
+		name, zpl, want string
+	}{
+		{"one format", "^XA^FDa^FS^XZ", "^XA^FDa^FS^PQ2^XZ"},
+		{"lower case", "^xa^fda^fs^xz", "^xa^fda^fs^PQ2^xz"},
+		{"several formats", "^XA^FDa^FS^XZ\n^XA^FDb^FS^xZ\n", "^XA^FDa^FS^PQ2^XZ\n^XA^FDb^FS^PQ2^xZ\n"},
+		{"code page 1252 data", "^XA^FDcaf\xe9 ok^FS^XZ", "^XA^FDcaf\xe9 ok^FS^PQ2^XZ"},
+		{"long s", "^XA^FDſſſ^FS^XZ", "^XA^FDſſſ^FS^PQ2^XZ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := insertQuantity(tt.zpl, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := insertQuantity("^XA^FDa^FS", 2); err == nil {
+		t.Error("no error for a payload without ^XZ")
+	}
+}
+
+func TestSendZPLCopies(t *testing.T) {
+	m := NewMockPrinter()
+	if err := SendZPLCopies(m, "^XA^FDcaf\xe9^FS^XZ", 3); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.Sent(), []string{"^XA^FDcaf\xe9^FS^PQ3^XZ"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+	if err := SendZPLCopies(m, "^XA^XZ", 0); err == nil {
+		t.Error("no error for 0 copies")
+	}
+}
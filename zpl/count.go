@@ -0,0 +1,32 @@
+package zpl
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// sendStats is the client-side bookkeeping of a connection, shared by the
+// copies of its options.
+type sendStats struct {
+	jobs   atomic.Uint64 // sends attempted, numbering each one
+	labels atomic.Uint64 // formats in the sends that succeeded
+}
+
+// countFormats returns the number of label formats, ended by ^XZ, in zpl.
+func countFormats(zpl string) int {
+	return strings.Count(strings.ToUpper(zpl), "^XZ")
+}
+
+// formatCounter is an io.Writer counting the formats in a stream written
+// to it in chunks, including a ^XZ split across two chunks.
+type formatCounter struct {
+	n    int
+	tail string
+}
+
+func (c *formatCounter) Write(b []byte) (int, error) {
+	s := c.tail + string(b)
+	c.n += countFormats(s)
+	c.tail = s[max(len(s)-2, 0):]
+	return len(b), nil
+}
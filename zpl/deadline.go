@@ -0,0 +1,51 @@
+package zpl
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// deadlines holds the deadlines set with SetDeadline, SetReadDeadline and
+// SetWriteDeadline. It has its own lock so they can be changed while a
+// send holds the printer's.
+type deadlines struct {
+	mu          sync.Mutex
+	read, write time.Time
+}
+
+func (d *deadlines) set(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.read, d.write = t, t
+}
+
+func (d *deadlines) setRead(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.read = t
+}
+
+func (d *deadlines) setWrite(t time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.write = t
+}
+
+// readBy returns the read deadline, zero if none is set.
+func (d *deadlines) readBy() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.read
+}
+
+// writeContext returns ctx bounded by the write deadline, if one is set.
+func (d *deadlines) writeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	d.mu.Lock()
+	t := d.write
+	d.mu.Unlock()
+	if t.IsZero() {
+		return ctx, func() {}
+	}
+	return context.WithDeadline(ctx, t)
+}
@@ -0,0 +1,129 @@
+package zpl
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// discoverWorkers bounds the number of concurrent probes of a scan.
+const discoverWorkers = 64
+
+// maxDiscoverHosts keeps a typo in the prefix length from starting a scan
+// of millions of addresses.
+const maxDiscoverHosts = 1 << 16
+
+// NetworkPrinterInfo describes a Zebra printer found by DiscoverZebraPrinters.
+type NetworkPrinterInfo struct {
+	Addr  string // host:port
+	Model string // model as reported by ~HI, such as "ZT410-200dpi"
+}
+
+// DiscoverNetworkPrinters scans the IPv4 subnet cidr, such as
+// "192.168.1.0/24", for hosts accepting connections on DefaultPort, and
+// returns their addresses in ascending order. timeout bounds each
+// connection attempt.
+func DiscoverNetworkPrinters(cidr string, timeout time.Duration) ([]string, error) {
+	hosts, err := subnetHosts(cidr)
+	if err != nil {
+		return nil, err
+	}
+	return scanHosts(hosts, func(addr string) (string, bool) {
+		conn, err := net.DialTimeout("tcp", addr, timeout)
+		if err != nil {
+			return "", false
+		}
+		conn.Close()
+		return addr, true
+	}), nil
+}
+
+// DiscoverZebraPrinters is like DiscoverNetworkPrinters but also sends ~HI
+// to every open port, keeping only hosts that answer like a Zebra printer.
+// timeout bounds both the connection and the answer.
+func DiscoverZebraPrinters(cidr string, timeout time.Duration) ([]NetworkPrinterInfo, error) {
+	hosts, err := subnetHosts(cidr)
+	if err != nil {
+		return nil, err
+	}
+	return scanHosts(hosts, func(addr string) (NetworkPrinterInfo, bool) {
+		p, err := NewNetworkPrinterWithTimeout(addr, timeout)
+		if err != nil {
+			return NetworkPrinterInfo{}, false
+		}
+		defer p.Close()
+		resp, err := query(p, "~HI", frames(1), timeout)
+		if err != nil {
+			return NetworkPrinterInfo{}, false
+		}
+		model, _, _ := strings.Cut(strings.Trim(strings.TrimSpace(resp), stx+etx), ",")
+		if model == "" {
+			return NetworkPrinterInfo{}, false
+		}
+		return NetworkPrinterInfo{Addr: addr, Model: model}, true
+	}), nil
+}
+
+// subnetHosts lists the host:port address of every host in cidr, leaving
+// out the network and broadcast addresses of subnets that have them.
+func subnetHosts(cidr string) ([]string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet %q: %w", cidr, err)
+	}
+	if !prefix.Addr().Is4() {
+		return nil, fmt.Errorf("invalid subnet %q: only IPv4 subnets can be scanned", cidr)
+	}
+	prefix = prefix.Masked()
+	size := 1 << (32 - prefix.Bits())
+	if size > maxDiscoverHosts {
+		return nil, fmt.Errorf("invalid subnet %q: more than %d addresses", cidr, maxDiscoverHosts)
+	}
+
+	port := strconv.Itoa(DefaultPort)
+	hosts := make([]string, 0, size)
+	addr := prefix.Addr()
+	for i := 0; i < size; i++ {
+		if size <= 2 || (i != 0 && i != size-1) {
+			hosts = append(hosts, net.JoinHostPort(addr.String(), port))
+		}
+		addr = addr.Next()
+	}
+	return hosts, nil
+}
+
+// scanHosts runs probe on every host with up to discoverWorkers at a time
+// and returns the results of successful probes in host order.
+func scanHosts[T any](hosts []string, probe func(addr string) (T, bool)) []T {
+	results := make([]T, len(hosts))
+	found := make([]bool, len(hosts))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(discoverWorkers, len(hosts)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], found[i] = probe(hosts[i])
+			}
+		}()
+	}
+	for i := range hosts {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var out []T
+	for i, ok := range found {
+		if ok {
+			out = append(out, results[i])
+		}
+	}
+	return out
+}
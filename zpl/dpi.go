@@ -0,0 +1,72 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DPI is a print head resolution in dots per inch. ZPL positions and sizes
+// are in dots, so the same coordinates land in different physical places
+// on printers of different resolutions.
+type DPI int
+
+// Common Zebra print head resolutions.
+const (
+	DPI152 DPI = 152
+	DPI203 DPI = 203
+	DPI300 DPI = 300
+	DPI600 DPI = 600
+)
+
+// MMToDots converts mm to the nearest whole number of dots at d.
+func (d DPI) MMToDots(mm float64) int {
+	return mmToDots(mm, int(d))
+}
+
+// DotsToMM converts dots at d to millimeters.
+func (d DPI) DotsToMM(dots int) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(dots) * 25.4 / float64(d)
+}
+
+// errNoDPI is recorded by the builder when a millimeter method is used
+// before SetDPI.
+var errNoDPI = errors.New("no resolution set: call SetDPI before using millimeters")
+
+// SetDPI sets the resolution the builder's millimeter methods convert at.
+// It applies to every format built afterwards.
+func (b *LabelBuilder) SetDPI(d DPI) *LabelBuilder {
+	if d <= 0 {
+		b.fail(fmt.Errorf("invalid resolution %d dpi", d))
+		return b
+	}
+	b.dpi = d
+	return b
+}
+
+// FieldMM is like Field with the origin in millimeters.
+func (b *LabelBuilder) FieldMM(x, y float64) *LabelBuilder {
+	if b.dpi <= 0 {
+		b.fail(errNoDPI)
+		return b
+	}
+	if !b.checkDotUnits() {
+		return b
+	}
+	return b.Field(b.dpi.MMToDots(x), b.dpi.MMToDots(y))
+}
+
+// TextMM is like Text with the origin and character size in millimeters.
+func (b *LabelBuilder) TextMM(x, y float64, font string, h, w float64, data string) *LabelBuilder {
+	if b.dpi <= 0 {
+		b.fail(errNoDPI)
+		return b
+	}
+	if !b.checkDotUnits() {
+		return b
+	}
+	d := b.dpi
+	return b.Text(d.MMToDots(x), d.MMToDots(y), font, d.MMToDots(h), d.MMToDots(w), data)
+}
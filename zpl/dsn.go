@@ -0,0 +1,122 @@
+package zpl
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenPrinter opens the printer described by dsn, a URL-style connection
+// string parsed by ParseDSN.
+func OpenPrinter(dsn string, opts ...Option) (PrinterConnection, error) {
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrinter(cfg, opts...)
+}
+
+// ParseDSN converts a connection string into the Config it stands for:
+//
+//	usb://                          the TLP 2844 defaults
+//	usb://0a5f:00d4                 vendor and product ID in hex
+//	tcp://192.168.1.50:9100         a network printer; the port is optional
+//	tcp://[fe80::1]?timeout=5s      with a dial and write timeout
+//	serial:///dev/ttyUSB0?baud=9600 a serial port; the baud rate is optional
+//	serial://COM3
+//
+// Malformed strings fail with an error wrapping ErrInvalidConfig that names
+// the offending part.
+func ParseDSN(dsn string) (Config, error) {
+	// USB IDs are hex, which url.Parse rejects as a port
+	if scheme, ids, ok := strings.Cut(dsn, "://"); ok && strings.EqualFold(scheme, "usb") {
+		return parseUSBDSN(dsn, ids)
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if u.User != nil || u.Fragment != "" || u.Opaque != "" {
+		return Config{}, fmt.Errorf("%w: dsn %q: unexpected user, fragment or opaque part", ErrInvalidConfig, dsn)
+	}
+	q := u.Query()
+	param := func(name string) string {
+		v := q.Get(name)
+		q.Del(name)
+		return v
+	}
+
+	var cfg Config
+	switch strings.ToLower(u.Scheme) {
+	case "tcp":
+		cfg.Type = TypeNetwork
+		if u.Host == "" {
+			return Config{}, fmt.Errorf("%w: tcp dsn %q: missing host", ErrInvalidConfig, dsn)
+		}
+		if u.Path != "" && u.Path != "/" {
+			return Config{}, fmt.Errorf("%w: tcp dsn %q: unexpected path %q", ErrInvalidConfig, dsn, u.Path)
+		}
+		cfg.Address = u.Host
+		if v := param("timeout"); v != "" {
+			if cfg.Timeout, err = time.ParseDuration(v); err != nil || cfg.Timeout < 0 {
+				return Config{}, fmt.Errorf("%w: tcp dsn %q: invalid timeout %q", ErrInvalidConfig, dsn, v)
+			}
+		}
+	case "serial":
+		cfg.Type = TypeSerial
+		if u.Host != "" && u.Path != "" {
+			return Config{}, fmt.Errorf("%w: serial dsn %q: give the port as serial:///dev/name or serial://COMn", ErrInvalidConfig, dsn)
+		}
+		if cfg.Port = u.Host + u.Path; cfg.Port == "" {
+			return Config{}, fmt.Errorf("%w: serial dsn %q: missing port", ErrInvalidConfig, dsn)
+		}
+		if v := param("baud"); v != "" {
+			if cfg.Baud, err = strconv.Atoi(v); err != nil || cfg.Baud <= 0 {
+				return Config{}, fmt.Errorf("%w: serial dsn %q: invalid baud rate %q", ErrInvalidConfig, dsn, v)
+			}
+		}
+	case "":
+		return Config{}, fmt.Errorf("%w: dsn %q: missing scheme, want usb://, tcp:// or serial://", ErrInvalidConfig, dsn)
+	default:
+		return Config{}, fmt.Errorf("%w: dsn %q: unknown scheme %q", ErrInvalidConfig, dsn, u.Scheme)
+	}
+	if len(q) > 0 {
+		name := slices.Min(slices.Collect(maps.Keys(q)))
+		return Config{}, fmt.Errorf("%w: %s dsn %q: unknown parameter %q", ErrInvalidConfig, u.Scheme, dsn, name)
+	}
+	return cfg, nil
+}
+
+// parseUSBDSN parses the ids after usb:// in dsn.
+func parseUSBDSN(dsn, ids string) (Config, error) {
+	cfg := Config{Type: TypeUSB}
+	if ids == "" {
+		return cfg, nil
+	}
+	vid, pid, ok := strings.Cut(ids, ":")
+	if !ok {
+		return Config{}, fmt.Errorf("%w: usb dsn %q: want vendor:product, got %q", ErrInvalidConfig, dsn, ids)
+	}
+	var err error
+	if cfg.VendorID, err = parseUSBID("vendor", vid); err != nil {
+		return Config{}, err
+	}
+	if cfg.ProductID, err = parseUSBID("product", pid); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// parseUSBID parses a hex USB vendor or product ID, with or without 0x.
+func parseUSBID(what, s string) (uint16, error) {
+	hex := strings.TrimPrefix(strings.ToLower(s), "0x")
+	id, err := strconv.ParseUint(hex, 16, 16)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: invalid usb %s id %q", ErrInvalidConfig, what, s)
+	}
+	return uint16(id), nil
+}
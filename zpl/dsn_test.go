@@ -0,0 +1,92 @@
+package zpl
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		dsn  string
+		want Config
+	}{
+		{"usb://", Config{Type: TypeUSB}},
+		{"USB://0a5f:00d4", Config{Type: TypeUSB, VendorID: 0x0a5f, ProductID: 0x00d4}},
+		{"usb://0x0A5F:0x00D4", Config{Type: TypeUSB, VendorID: 0x0a5f, ProductID: 0x00d4}},
+		{"tcp://192.168.1.50:9100", Config{Type: TypeNetwork, Address: "192.168.1.50:9100"}},
+		{"tcp://printer.local", Config{Type: TypeNetwork, Address: "printer.local"}},
+		{"tcp://printer.local/", Config{Type: TypeNetwork, Address: "printer.local"}},
+		{"tcp://[fe80::1]?timeout=5s", Config{Type: TypeNetwork, Address: "[fe80::1]", Timeout: 5 * time.Second}},
+		{"TCP://[fe80::1]:6101", Config{Type: TypeNetwork, Address: "[fe80::1]:6101"}},
+		{"serial:///dev/ttyUSB0?baud=9600", Config{Type: TypeSerial, Port: "/dev/ttyUSB0", Baud: 9600}},
+		{"serial://COM3", Config{Type: TypeSerial, Port: "COM3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dsn, func(t *testing.T) {
+			got, err := ParseDSN(tt.dsn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDSN(%q) =\n%+v\nwant\n%+v", tt.dsn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDSNDefaultPort(t *testing.T) {
+	tests := []struct {
+		dsn, addr string
+	}{
+		{"tcp://192.168.1.50", "192.168.1.50:9100"},
+		{"tcp://192.168.1.50:", "192.168.1.50:9100"},
+		{"tcp://192.168.1.50:6101", "192.168.1.50:6101"},
+		{"tcp://[fe80::1]", "[fe80::1]:9100"},
+	}
+	for _, tt := range tests {
+		cfg, err := ParseDSN(tt.dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := withDefaultPort(cfg.Address); got != tt.addr {
+			t.Errorf("ParseDSN(%q) dials %q, want %q", tt.dsn, got, tt.addr)
+		}
+	}
+}
+
+func TestParseDSNInvalid(t *testing.T) {
+	tests := []struct {
+		name, dsn string
+	}{
+		{"empty", ""},
+		{"no scheme", "192.168.1.50:9100"},
+		{"unknown scheme", "lpt://1"},
+		{"malformed url", "tcp://[fe80::1"},
+		{"user info", "tcp://admin@printer"},
+		{"fragment", "tcp://printer#a"},
+		{"opaque", "tcp:printer"},
+		{"usb one id", "usb://0a5f"},
+		{"usb bad vendor", "usb://zz:00d4"},
+		{"usb zero product", "usb://0a5f:0"},
+		{"usb id too large", "usb://10000:00d4"},
+		{"tcp no host", "tcp:///path"},
+		{"tcp path", "tcp://printer/queue"},
+		{"tcp bad timeout", "tcp://printer?timeout=5"},
+		{"tcp negative timeout", "tcp://printer?timeout=-1s"},
+		{"tcp unknown option", "tcp://printer?baud=9600"},
+		{"serial no port", "serial://"},
+		{"serial host and path", "serial://COM3/dev/ttyS0"},
+		{"serial bad baud", "serial://COM3?baud=fast"},
+		{"serial zero baud", "serial://COM3?baud=0"},
+		{"serial unknown option", "serial://COM3?timeout=1s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDSN(tt.dsn)
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("ParseDSN(%q) = %v, want ErrInvalidConfig", tt.dsn, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package zpl
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Encoding is a character set for field data, selected on the printer with
+// ^CI. Without one the printer reads field data as its default code page,
+// so accented letters in UTF-8 strings print as garbage.
+type Encoding int
+
+// Encodings, by their ^CI number.
+const (
+	// EncodingLatin1 is Zebra code page 1252: ISO 8859-1 plus the Windows
+	// additions such as € and curly quotes. Field data is transcoded to it.
+	EncodingLatin1 Encoding = 27
+	// EncodingUTF8 sends field data as UTF-8 unchanged.
+	EncodingUTF8 Encoding = 28
+)
+
+// command returns the ^CI command selecting e.
+func (e Encoding) command() string {
+	return fmt.Sprintf("^CI%d", int(e))
+}
+
+// encode converts s, a UTF-8 string, to e.
+func (e Encoding) encode(s string) (string, error) {
+	switch e {
+	case EncodingUTF8:
+		if !utf8.ValidString(s) {
+			return "", fmt.Errorf("field data %q is not valid UTF-8", s)
+		}
+		return s, nil
+	case EncodingLatin1:
+		return encodeCP1252(s)
+	}
+	return "", fmt.Errorf("unsupported encoding ^CI%d", int(e))
+}
+
+// cp1252 maps the characters Windows-1252 places in 0x80-0x9F.
+var cp1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86,
+	'‡': 0x87, 'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C,
+	'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95,
+	'–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeCP1252 transcodes s to code page 1252, failing on the first
+// character it cannot represent.
+func encodeCP1252(s string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i, r := range s {
+		switch c, ok := cp1252[r]; {
+		case ok:
+			b.WriteByte(c)
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			b.WriteByte(byte(r))
+		default:
+			return "", fmt.Errorf("character %q at offset %d cannot be encoded in code page 1252", r, i)
+		}
+	}
+	return b.String(), nil
+}
+
+// withEncoding selects e in every format of zpl and transcodes it. A
+// payload that already selects an encoding with ^CI is left unchanged.
+func withEncoding(zpl string, e Encoding) (string, error) {
+	if strings.Contains(strings.ToUpper(zpl), "^CI") {
+		return zpl, nil
+	}
+	zpl, err := e.encode(zpl)
+	if err != nil {
+		return "", err
+	}
+	return insertAfterStart(zpl, e.command()), nil
+}
+
+// SetEncoding makes every format built afterwards select e with ^CI and
+// transcodes field data to it. Without it, field data is written as given.
+func (b *LabelBuilder) SetEncoding(e Encoding) *LabelBuilder {
+	if _, err := e.encode(""); err != nil {
+		b.fail(err)
+		return b
+	}
+	b.encoding = e
+	return b
+}
+
+// encodeData converts field data to the builder's encoding, recording an
+// error if it cannot be represented.
+func (b *LabelBuilder) encodeData(data string) (string, bool) {
+	if b.encoding == 0 {
+		return data, true
+	}
+	data, err := b.encoding.encode(data)
+	if err != nil {
+		b.fail(err)
+		return "", false
+	}
+	return data, true
+}
@@ -0,0 +1,91 @@
+package zpl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeCP1252(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello", "Hello"},
+		{"café", "caf\xe9"},
+		{"Müller Straße", "M\xfcller Stra\xdfe"},
+		{"¡¿ÿ", "\xa1\xbf\xff"},
+		{"€5", "\x805"},
+		{"“quoted” – ‘x’…", "\x93quoted\x94 \x96 \x91x\x92\x85"},
+		{"ŠšŽžŒœŸ™", "\x8a\x9a\x8e\x9e\x8c\x9c\x9f\x99"},
+	}
+	for _, tt := range tests {
+		got, err := EncodingLatin1.encode(tt.in)
+		if err != nil {
+			t.Fatalf("encode(%q): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("encode(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeUnmappable(t *testing.T) {
+	tests := []struct {
+		enc Encoding
+		in  string
+	}{
+		{EncodingLatin1, "日本"},
+		{EncodingLatin1, "ok ↑"},
+		{EncodingLatin1, "Łódź"},
+		{EncodingLatin1, "\u0081"}, // unassigned in code page 1252
+		{EncodingLatin1, "\xff"},   // invalid UTF-8 decodes as U+FFFD
+		{EncodingUTF8, "caf\xe9"},
+		{Encoding(13), "a"},
+	}
+	for _, tt := range tests {
+		if got, err := tt.enc.encode(tt.in); err == nil {
+			t.Errorf("encode(%d, %q) = %q, want an error", tt.enc, tt.in, got)
+		}
+	}
+	if b := NewLabel().SetEncoding(EncodingLatin1).Text(10, 10, "0", 30, 30, "日本"); b.Err() == nil {
+		t.Error("Text with unmappable data recorded no error")
+	}
+}
+
+func TestSetEncodingHeader(t *testing.T) {
+	b := NewLabel().SetEncoding(EncodingLatin1).Text(10, 10, "0", 30, 30, "café")
+	if err := b.Err(); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	if !strings.HasPrefix(got, "^XA\n^CI27\n") {
+		t.Errorf("format does not start with ^CI27: %q", got)
+	}
+	if !strings.Contains(got, "^FDcaf\xe9^FS") {
+		t.Errorf("field data is not in code page 1252: %q", got)
+	}
+	if b := NewLabel().SetEncoding(Encoding(13)); b.Err() == nil {
+		t.Error("SetEncoding with an unsupported encoding recorded no error")
+	}
+}
+
+func TestWithEncoding(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"utf8", "^XA^FDé^FS^XZ", "^XA^CI28^FDé^FS^XZ"},
+		{"every format", "^XA^FDa^FS^XZ^xa^FDb^FS^xz", "^XA^CI28^FDa^FS^XZ^xa^CI28^FDb^FS^xz"},
+		{"already selected", "^XA^ci27^FD\xe9^FS^XZ", "^XA^ci27^FD\xe9^FS^XZ"},
+	}
+	for _, tt := range tests {
+		got, err := withEncoding(tt.in, EncodingUTF8)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: withEncoding(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+	if _, err := withEncoding("^XA^FD日本^FS^XZ", EncodingLatin1); err == nil {
+		t.Error("withEncoding with unmappable data succeeded")
+	}
+}
@@ -0,0 +1,47 @@
+package zpl
+
+import "errors"
+
+// Errors returned by the printer types, wrapped with details. Test for them
+// with errors.Is.
+var (
+	// ErrPrinterNotFound means no USB device matched the requested IDs.
+	ErrPrinterNotFound = errors.New("printer not found")
+	// ErrInterfaceNotClaimed means the USB interface could not be claimed,
+	// usually because another driver or process holds it.
+	ErrInterfaceNotClaimed = errors.New("failed to claim interface")
+	// ErrUSBPermission means the operating system denied access to the
+	// USB device, as opposed to the device itself failing.
+	ErrUSBPermission = errors.New("permission denied on USB device")
+	// ErrNotPaired means a Bluetooth printer could not be reached because
+	// it is not paired with this host, is switched off or is out of range.
+	ErrNotPaired = errors.New("bluetooth device not paired or unreachable")
+	// ErrNoEndpoints means the claimed USB interface has no endpoints at
+	// all, usually because it is not the printer's interface. Errors
+	// wrapping it also wrap ErrNoOutEndpoint.
+	ErrNoEndpoints = errors.New("USB interface has no endpoints")
+	// ErrNoOutEndpoint means the USB interface has no usable OUT endpoint.
+	ErrNoOutEndpoint = errors.New("no OUT endpoint found")
+	// ErrNoInEndpoint means the USB interface has no IN endpoint, so the
+	// printer cannot send responses back.
+	ErrNoInEndpoint = errors.New("no IN endpoint found")
+	// ErrNotConnected means the connection to the printer could not be
+	// established or broke while in use. Sends that fail with it are safe
+	// to retry.
+	ErrNotConnected = errors.New("printer not connected")
+	// ErrNotBidirectional means a query was made on a connection that
+	// cannot read responses back from the printer.
+	ErrNotBidirectional = errors.New("printer connection cannot read responses")
+	// ErrPrinterFault means the printer reported a condition that stops it
+	// from printing, such as paper out or an open head.
+	ErrPrinterFault = errors.New("printer fault")
+	// ErrPrinterPaused means the printer is paused, from its front panel or
+	// by Pause, and holds formats without printing them until resumed.
+	ErrPrinterPaused = errors.New("printer paused")
+	// ErrIncompleteFormat means a payload checked by strict validation has
+	// a label format missing its ^XA or ^XZ.
+	ErrIncompleteFormat = errors.New("incomplete label format")
+	// ErrMalformedResponse means the printer answered a query with data
+	// that could not be parsed.
+	ErrMalformedResponse = errors.New("malformed printer response")
+)
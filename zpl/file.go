@@ -0,0 +1,19 @@
+package zpl
+
+import (
+	"fmt"
+	"os"
+)
+
+// SendFile streams the ZPL file at path to p with SendZPLReader, adding the
+// trailing newline only at the end. Failing to read the file returns an
+// error wrapping the *fs.PathError, so errors.Is(err, fs.ErrNotExist) and
+// similar checks tell it apart from a send error.
+func SendFile(p PrinterConnection, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open ZPL file: %w", err)
+	}
+	defer f.Close()
+	return p.SendZPLReader(f)
+}
@@ -0,0 +1,115 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DownloadFont uploads a TrueType font to printer memory with ~DY in binary
+// format, so labels can print with it through FontText. name is an object
+// name such as "BRAND", "BRAND.TTF" or "E:BRAND.TTF"; the drive defaults to
+// R: (DRAM), which is cleared at power off, so use E: to keep the font.
+//
+// The header carries the exact byte count of ttf, which is sent unchanged
+// with RawSend; the binary format has no checksum.
+func DownloadFont(p PrinterConnection, name string, ttf []byte) error {
+	obj, err := fontName(name)
+	if err != nil {
+		return err
+	}
+	if len(ttf) == 0 {
+		return errors.New("font data is empty")
+	}
+	// ~DY takes the name without extension; T selects .TTF
+	drive, file, _ := strings.Cut(strings.TrimSuffix(obj, ".TTF"), ":")
+	header := fmt.Sprintf("~DY%s:%s,B,T,%d,,", drive, file, len(ttf))
+	return p.RawSend(append([]byte(header), ttf...))
+}
+
+// FontText adds a text field at (x, y) printed with the downloaded font
+// name, with character height h and width w in dots. name follows the same
+// rules as in DownloadFont.
+func (b *LabelBuilder) FontText(x, y int, name string, h, w int, data string) *LabelBuilder {
+	obj, err := fontName(name)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	if !b.field(x, y) {
+		return b
+	}
+	fmt.Fprintf(&b.body, "^A@N,%d,%d,%s", h, w, obj)
+	return b.Data(data)
+}
+
+// Smallest and largest character height and width ^CF accepts, in dots.
+const (
+	minDefaultFontSize = 10
+	maxDefaultFontSize = 32000
+)
+
+// DefaultFont sets with ^CF the font, and its character height h and width
+// w in dots, of text fields that choose none, such as Text with an empty
+// font. Both are 10 to 32000 dots, and w may be 0 to scale it with h.
+// font is a printer font letter or digit, or a letter given to a
+// downloaded font with FontAlias.
+func (b *LabelBuilder) DefaultFont(font string, h, w int) *LabelBuilder {
+	if !b.checkFontLetter(font) {
+		return b
+	}
+	if h < minDefaultFontSize || h > maxDefaultFontSize || w != 0 && (w < minDefaultFontSize || w > maxDefaultFontSize) {
+		b.fail(fmt.Errorf("invalid default font size %dx%d: height and width must be between %d and %d, or width 0", h, w, minDefaultFontSize, maxDefaultFontSize))
+		return b
+	}
+	return b.setup("^CF", fmt.Sprintf("^CF%s,%d,%d", strings.ToUpper(font), h, w))
+}
+
+// FontAlias gives the downloaded font name the font letter letter with
+// ^CW, so Text and DefaultFont can print with it like a built-in font.
+// Aliasing a printer font letter hides the built-in font for the format.
+// name follows the same rules as in DownloadFont. Aliases are placed ahead
+// of the ^CF of DefaultFont, which may use them.
+func (b *LabelBuilder) FontAlias(letter, name string) *LabelBuilder {
+	if !b.checkFontLetter(letter) {
+		return b
+	}
+	obj, err := fontName(name)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	b.ensureOpen()
+	prefix := "^CW" + strings.ToUpper(letter)
+	cmd := prefix + "," + obj
+	for i, c := range b.header {
+		if strings.HasPrefix(c, prefix+",") {
+			b.header[i] = cmd
+			return b
+		}
+	}
+	i := slices.IndexFunc(b.header, func(c string) bool { return strings.HasPrefix(c, "^CF") })
+	if i < 0 {
+		i = len(b.header)
+	}
+	b.header = slices.Insert(b.header, i, cmd)
+	return b
+}
+
+// fontLetters are the names a font can be selected by.
+const fontLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// checkFontLetter records an error unless font is a single letter or digit.
+func (b *LabelBuilder) checkFontLetter(font string) bool {
+	if len(font) != 1 || !strings.Contains(fontLetters, strings.ToUpper(font)) {
+		b.fail(fmt.Errorf("invalid font %q: must be a single letter or digit", font))
+		return false
+	}
+	return true
+}
+
+// fontName normalizes name to the printer's D:NAME.TTF form.
+func fontName(name string) (string, error) {
+	return objectName(name, "TTF", "font")
+}
@@ -0,0 +1,92 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxFieldNumber is the highest ^FN field number the printer accepts.
+const maxFieldNumber = 9999
+
+// StoreFormat downloads zpl to printer memory as the stored format name
+// with ^DF, so labels can recall it with LabelBuilder.Recall and send only
+// their variable fields. name is an object name such as "SHIP", "SHIP.ZPL"
+// or "E:SHIP.ZPL"; the drive defaults to R: (DRAM), which is cleared at
+// power off.
+//
+// zpl is a single label format, with or without its ^XA/^XZ. Fields to be
+// filled in at recall time are marked with ^FN, see LabelBuilder.Variable.
+func StoreFormat(p PrinterConnection, name, zpl string) error {
+	obj, err := formatName(name)
+	if err != nil {
+		return err
+	}
+	body := strings.TrimSpace(zpl)
+	if hasFoldAt(body, 0, "^XA") {
+		body = strings.TrimSpace(body[3:])
+	}
+	if hasFoldAt(body, len(body)-3, "^XZ") {
+		body = strings.TrimSpace(body[:len(body)-3])
+	}
+	if body == "" {
+		return errors.New("stored format is empty")
+	}
+	if indexFold(body, "^XA", 0) >= 0 || indexFold(body, "^XZ", 0) >= 0 {
+		return errors.New("stored format must be a single label format")
+	}
+	return p.SendZPL(fmt.Sprintf("^XA\n^DF%s^FS\n%s\n^XZ\n", obj, body))
+}
+
+// Recall makes the current format print the stored format name with ^XF,
+// with the fields set by Fill. name follows the same rules as in
+// StoreFormat.
+func (b *LabelBuilder) Recall(name string) *LabelBuilder {
+	obj, err := formatName(name)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	return b.setup("^XF", "^XF"+obj+"^FS")
+}
+
+// Variable terminates the current field with ^FN n instead of field data,
+// marking it as variable field n of a format passed to StoreFormat.
+func (b *LabelBuilder) Variable(n int) *LabelBuilder {
+	if !b.checkFieldNumber(n) {
+		return b
+	}
+	b.ensureOpen()
+	fmt.Fprintf(&b.body, "^FN%d^FS\n", n)
+	b.fieldOpen = false
+	return b
+}
+
+// Fill sets variable field n of the recalled format to data.
+func (b *LabelBuilder) Fill(n int, data string) *LabelBuilder {
+	if !b.checkFieldNumber(n) {
+		return b
+	}
+	data, ok := b.encodeData(data)
+	if !ok {
+		return b
+	}
+	b.ensureOpen()
+	b.closeField()
+	fmt.Fprintf(&b.body, "^FN%d^FD%s^FS\n", n, data)
+	return b
+}
+
+// checkFieldNumber records an error for a field number out of range.
+func (b *LabelBuilder) checkFieldNumber(n int) bool {
+	if n < 1 || n > maxFieldNumber {
+		b.fail(fmt.Errorf("invalid field number %d: must be between 1 and %d", n, maxFieldNumber))
+		return false
+	}
+	return true
+}
+
+// formatName normalizes name to the printer's D:NAME.ZPL form.
+func formatName(name string) (string, error) {
+	return objectName(name, "ZPL", "format")
+}
@@ -0,0 +1,36 @@
+package zpl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStoreFormat(t *testing.T) {
+	tests := []struct {
+		name, zpl, want string
+	}{
+		{"wrapped", "^XA^FO10,10^FN1^FS^XZ", "^XA\n^DFR:SHIP.ZPL^FS\n^FO10,10^FN1^FS\n^XZ\n"},
+		{"bare", " ^FO10,10^FN1^FS\n", "^XA\n^DFR:SHIP.ZPL^FS\n^FO10,10^FN1^FS\n^XZ\n"},
+		{"lower case", "^xa\n^fo10,10^fn1^fs\n^xz\n", "^XA\n^DFR:SHIP.ZPL^FS\n^fo10,10^fn1^fs\n^XZ\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockPrinter()
+			if err := StoreFormat(m, "SHIP", tt.zpl); err != nil {
+				t.Fatal(err)
+			}
+			if got := m.Sent(); !reflect.DeepEqual(got, []string{tt.want}) {
+				t.Errorf("sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreFormatInvalid(t *testing.T) {
+	for _, zpl := range []string{"", "^XA^XZ", "^xa ^xz", "^XA^FDa^FS^xz^xa^FDb^FS^XZ", "^FDa^FS^xZ^FDb^FS"} {
+		m := NewMockPrinter()
+		if err := StoreFormat(m, "SHIP", zpl); err == nil {
+			t.Errorf("StoreFormat(%q) sent %q, want an error", zpl, m.Sent())
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"regexp"
+	"strings"
+)
+
+// DefaultThreshold is the luminance below which a pixel prints black.
+const DefaultThreshold = 128
+
+// GraphicOption configures how images are converted to ZPL graphics.
+type GraphicOption func(*graphicOptions)
+
+type graphicOptions struct {
+	threshold uint8
+	encoding  GraphicEncoding
+}
+
+func newGraphicOptions(opts []GraphicOption) graphicOptions {
+	o := graphicOptions{threshold: DefaultThreshold}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithThreshold sets the 0-255 luminance cutoff used to reduce an image to
+// one bit per pixel: darker pixels print, lighter ones stay blank.
+func WithThreshold(cutoff uint8) GraphicOption {
+	return func(o *graphicOptions) {
+		o.threshold = cutoff
+	}
+}
+
+// WithGraphicEncoding sets how the graphic data is written out; the
+// default is GraphicHex.
+func WithGraphicEncoding(e GraphicEncoding) GraphicOption {
+	return func(o *graphicOptions) {
+		o.encoding = e
+	}
+}
+
+// ImageToZPL converts img to a monochrome ^GFA field placed at (x, y).
+// Transparent pixels are left blank. The byte counts in the field are those
+// of the uncompressed image, whichever encoding is chosen.
+func ImageToZPL(img image.Image, x, y int, opts ...GraphicOption) (string, error) {
+	if x < 0 || y < 0 {
+		return "", fmt.Errorf("invalid field origin %d,%d: coordinates must be non-negative", x, y)
+	}
+	o := newGraphicOptions(opts)
+	data, bytesPerRow, err := monochrome(img, o)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := o.encoding.encode(data)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("^FO%d,%d^GFA,%d,%d,%d,%s^FS\n",
+		x, y, len(data), len(data), bytesPerRow, encoded), nil
+}
+
+// StoreGraphic downloads img to printer memory with ~DG so labels can
+// recall it with RecallGraphic instead of embedding it every time. name is
+// an object name such as "LOGO", "LOGO.GRF" or "E:LOGO.GRF"; the drive
+// defaults to R: (DRAM).
+func StoreGraphic(p PrinterConnection, name string, img image.Image, opts ...GraphicOption) error {
+	obj, err := graphicName(name)
+	if err != nil {
+		return err
+	}
+	o := newGraphicOptions(opts)
+	data, bytesPerRow, err := monochrome(img, o)
+	if err != nil {
+		return err
+	}
+	encoded, err := o.encoding.encode(data)
+	if err != nil {
+		return err
+	}
+	return p.SendZPL(fmt.Sprintf("~DG%s,%d,%d,%s", obj, len(data), bytesPerRow, encoded))
+}
+
+// RecallGraphic returns a field that prints the graphic stored under name
+// at (x, y). name follows the same rules as in StoreGraphic; a name
+// StoreGraphic would reject is used unchanged.
+func RecallGraphic(name string, x, y int) string {
+	obj, err := graphicName(name)
+	if err != nil {
+		obj = name
+	}
+	return fmt.Sprintf("^FO%d,%d^XG%s,1,1^FS\n", x, y, obj)
+}
+
+// graphicName normalizes name to the printer's D:NAME.GRF form.
+func graphicName(name string) (string, error) {
+	return objectName(name, "GRF", "graphic")
+}
+
+// objectName normalizes name, optionally with a drive and extension ext, to
+// the printer's D:NAME.EXT form. The drive defaults to R: (DRAM).
+func objectName(name, ext, kind string) (string, error) {
+	re := regexp.MustCompile(`^(?:([REBA]):)?([A-Z0-9_]{1,8})(?:\.` + ext + `)?$`)
+	m := re.FindStringSubmatch(strings.ToUpper(name))
+	if m == nil {
+		return "", fmt.Errorf("invalid %s name %q: want up to 8 letters, digits or underscores, optionally as D:NAME.%s", kind, name, ext)
+	}
+	drive := m[1]
+	if drive == "" {
+		drive = "R"
+	}
+	return drive + ":" + m[2] + "." + ext, nil
+}
+
+// monochrome packs img into rows of 1-bit pixels, most significant bit
+// first, with a set bit meaning a black dot. Each row is padded to a whole
+// byte.
+func monochrome(img image.Image, o graphicOptions) ([]byte, int, error) {
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil, 0, errors.New("image is empty")
+	}
+	bytesPerRow := (bounds.Dx() + 7) / 8
+	data := make([]byte, bytesPerRow*bounds.Dy())
+
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		row := data[(py-bounds.Min.Y)*bytesPerRow:]
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			r, g, b, a := img.At(px, py).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// ITU-R 601 luma on the 16-bit channels, scaled to 8 bits
+			lum := (299*r + 587*g + 114*b) / 1000 >> 8
+			if lum < uint32(o.threshold) {
+				col := px - bounds.Min.X
+				row[col/8] |= 0x80 >> (col % 8)
+			}
+		}
+	}
+	return data, bytesPerRow, nil
+}
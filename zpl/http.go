@@ -0,0 +1,61 @@
+package zpl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxHTTPPayload bounds the body of a print request.
+const maxHTTPPayload = 16 << 20
+
+// NewHTTPHandler returns a handler that makes p a print gateway for
+// services without access to the printer:
+//
+//	POST /print   sends the request body as ZPL, answering 200 once sent
+//	GET  /status  answers the parsed ~HS status as JSON
+//
+// Payloads failing strict validation are answered with 400, printer errors
+// with 502, and /status on a connection that cannot read responses with
+// 501. Each answer other than 200 carries the error as plain text.
+func NewHTTPHandler(p PrinterConnection) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /print", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPPayload))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, fmt.Sprintf("payload larger than %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, fmt.Sprintf("failed to read payload: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := p.SendZPL(string(body)); err != nil {
+			var invalid *ValidationError
+			if errors.As(err, &invalid) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		s, err := hostStatus(p)
+		if err != nil {
+			code := http.StatusBadGateway
+			if errors.Is(err, ErrNotBidirectional) {
+				code = http.StatusNotImplemented
+			}
+			http.Error(w, err.Error(), code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s)
+	})
+	return mux
+}
@@ -0,0 +1,97 @@
+package zpl
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// endless is a reader of infinitely many bytes.
+type endless struct{}
+
+func (endless) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 'A'
+	}
+	return len(b), nil
+}
+
+func TestHTTPHandlerPrint(t *testing.T) {
+	strict, err := NewTransportPrinter(new(bufTransport), WithStrictValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := NewMockPrinter()
+	failing.FailOn(1, ErrNotConnected)
+
+	tests := []struct {
+		name   string
+		p      PrinterConnection
+		method string
+		body   io.Reader
+		code   int
+	}{
+		{"sent", NewMockPrinter(), "POST", strings.NewReader("^XA^XZ"), http.StatusOK},
+		{"wrong method", NewMockPrinter(), "GET", nil, http.StatusMethodNotAllowed},
+		{"too large", NewMockPrinter(), "POST", io.LimitReader(endless{}, maxHTTPPayload+1), http.StatusRequestEntityTooLarge},
+		{"invalid", strict, "POST", strings.NewReader("^XA^FDa^FS"), http.StatusBadRequest},
+		{"printer error", failing, "POST", strings.NewReader("^XA^XZ"), http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewHTTPHandler(tt.p).ServeHTTP(w, httptest.NewRequest(tt.method, "/print", tt.body))
+			if w.Code != tt.code {
+				t.Errorf("got %d %q, want %d", w.Code, w.Body, tt.code)
+			}
+		})
+	}
+}
+
+func TestHTTPHandlerStatus(t *testing.T) {
+	s, err := NewTestServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	p, err := NewNetworkPrinter(s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	w := httptest.NewRecorder()
+	NewHTTPHandler(p).ServeHTTP(w, httptest.NewRequest("GET", "/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d %q, want 200", w.Code, w.Body)
+	}
+	var status HostStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := ParseHostStatus(DefaultTestHostStatus); status != want {
+		t.Errorf("got %+v, want %+v", status, want)
+	}
+
+	w = httptest.NewRecorder()
+	NewHTTPHandler(NewMockPrinter()).ServeHTTP(w, httptest.NewRequest("GET", "/status", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("got %d for a write-only printer, want 501", w.Code)
+	}
+}
+
+func TestHTTPHandlerStatusError(t *testing.T) {
+	p, err := NewTransportPrinter(new(bufTransport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+	w := httptest.NewRecorder()
+	NewHTTPHandler(p).ServeHTTP(w, httptest.NewRequest("GET", "/status", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("got %d %q, want 502", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,71 @@
+package zpl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PrinterInfo is the printer's answer to ~HI (host identification).
+type PrinterInfo struct {
+	Model     string // such as "ZT410-200dpi"
+	Firmware  string // firmware version, such as "V75.20.01Z"
+	DotsPerMM int    // print resolution: 6, 8, 12 or 24
+	MemoryKB  int    // installed memory
+	Options   string // letter codes of the recognizable options, if any
+}
+
+// DPI returns the print resolution as a DPI, for LabelBuilder.SetDPI, or 0
+// if DotsPerMM is not one the package knows.
+func (i PrinterInfo) DPI() DPI {
+	switch i.DotsPerMM {
+	case 6:
+		return DPI152
+	case 8:
+		return DPI203
+	case 12:
+		return DPI300
+	case 24:
+		return DPI600
+	}
+	return 0
+}
+
+// Identify sends ~HI and parses the printer's model, firmware version,
+// resolution and memory. It needs a bidirectional connection.
+func Identify(p PrinterConnection) (PrinterInfo, error) {
+	raw, err := query(p, "~HI", frames(1), responseTimeout)
+	if err != nil {
+		return PrinterInfo{}, err
+	}
+	return ParseHostIdentification(raw)
+}
+
+// ParseHostIdentification decodes a raw ~HI response, as read from the
+// printer with its STX/ETX framing.
+func ParseHostIdentification(raw string) (PrinterInfo, error) {
+	resp, _, _ := strings.Cut(strings.TrimSpace(raw), etx)
+	fields := strings.Split(strings.Trim(strings.TrimSpace(resp), stx), ",")
+	if len(fields) < 4 || fields[0] == "" {
+		return PrinterInfo{}, fmt.Errorf("%w: identification has too few fields", ErrMalformedResponse)
+	}
+	dpm, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+	if err != nil {
+		return PrinterInfo{}, fmt.Errorf("%w: dots per mm %q", ErrMalformedResponse, fields[2])
+	}
+	mem := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(fields[3])), "KB")
+	kb, err := strconv.Atoi(mem)
+	if err != nil {
+		return PrinterInfo{}, fmt.Errorf("%w: memory %q", ErrMalformedResponse, fields[3])
+	}
+	info := PrinterInfo{
+		Model:     strings.TrimSpace(fields[0]),
+		Firmware:  strings.TrimSpace(fields[1]),
+		DotsPerMM: dpm,
+		MemoryKB:  kb,
+	}
+	if len(fields) > 4 {
+		info.Options = strings.TrimSpace(fields[4])
+	}
+	return info, nil
+}
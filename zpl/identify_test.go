@@ -0,0 +1,60 @@
+package zpl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseHostIdentification(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want PrinterInfo
+		dpi  DPI
+	}{
+		{"GK420d", "\x02GK420d,V61.17.16Z,8,8176KB\x03\r\n",
+			PrinterInfo{Model: "GK420d", Firmware: "V61.17.16Z", DotsPerMM: 8, MemoryKB: 8176}, DPI203},
+		{"with options", "\x02ZT410-300dpi,V75.20.01Z,12,8192KB,CT\x03\r\n",
+			PrinterInfo{Model: "ZT410-300dpi", Firmware: "V75.20.01Z", DotsPerMM: 12, MemoryKB: 8192, Options: "CT"}, DPI300},
+		{"padded", "\x02ZM400-600dpi ,V53.17.7Z ,24, 6144KB\x03",
+			PrinterInfo{Model: "ZM400-600dpi", Firmware: "V53.17.7Z", DotsPerMM: 24, MemoryKB: 6144}, DPI600},
+		{"no framing", "ZD410-152dpi,V84.20.18Z,6,4096kb",
+			PrinterInfo{Model: "ZD410-152dpi", Firmware: "V84.20.18Z", DotsPerMM: 6, MemoryKB: 4096}, DPI152},
+		{"unknown resolution", "\x02XYZ,V1,16,512\x03",
+			PrinterInfo{Model: "XYZ", Firmware: "V1", DotsPerMM: 16, MemoryKB: 512}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHostIdentification(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHostIdentification(%q) =\n%+v\nwant\n%+v", tt.raw, got, tt.want)
+			}
+			if dpi := got.DPI(); dpi != tt.dpi {
+				t.Errorf("DPI() = %v, want %v", dpi, tt.dpi)
+			}
+		})
+	}
+}
+
+func TestParseHostIdentificationMalformed(t *testing.T) {
+	tests := []struct {
+		name, raw string
+	}{
+		{"empty", ""},
+		{"framing only", "\x02\x03\r\n"},
+		{"too few fields", "\x02GK420d,V61.17.16Z,8\x03"},
+		{"no model", "\x02,V61.17.16Z,8,8176KB\x03"},
+		{"bad resolution", "\x02GK420d,V61.17.16Z,eight,8176KB\x03"},
+		{"bad memory", "\x02GK420d,V61.17.16Z,8,8MB\x03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseHostIdentification(tt.raw); !errors.Is(err, ErrMalformedResponse) {
+				t.Errorf("ParseHostIdentification(%q) = %v, want ErrMalformedResponse", tt.raw, err)
+			}
+		})
+	}
+}
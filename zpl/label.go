@@ -0,0 +1,265 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// LabelBuilder assembles a ZPL label format field by field. Every format is
+// wrapped in ^XA/^XZ and every field is terminated with ^FS, so the result
+// of String can be passed straight to SendZPL.
+//
+// Builder methods never panic. The first invalid argument is recorded and
+// reported by Err; the offending element is left out of the label.
+type LabelBuilder struct {
+	done      strings.Builder // completed formats
+	header    []string        // format setup commands, emitted right after ^XA
+	body      strings.Builder // fields of the current format
+	quantity  int             // ^PQ copies of the current format, 0 for the default
+	dpi       DPI             // resolution for the millimeter methods, 0 if unset
+	encoding  Encoding        // ^CI character set of field data, 0 if unset
+	units     Unit            // ^MU units of the fields, 0 for dots
+	dx, dy    int             // Offset added to every field origin
+	reverse   bool            // Reverse is pending for the next field
+	open      bool
+	fieldOpen bool
+	err       error
+}
+
+// NewLabel returns an empty label builder.
+func NewLabel() *LabelBuilder {
+	return &LabelBuilder{}
+}
+
+// Start begins a new label format, ending the current one if needed.
+// Calling it is optional for single-label builders.
+func (b *LabelBuilder) Start() *LabelBuilder {
+	b.End()
+	b.open = true
+	return b
+}
+
+// End closes the current label format with ^XZ.
+func (b *LabelBuilder) End() *LabelBuilder {
+	if b.open {
+		b.done.WriteString(b.format())
+		b.header = nil
+		b.body.Reset()
+		b.quantity = 0
+		b.open = false
+		b.fieldOpen = false
+		b.reverse = false
+	}
+	return b
+}
+
+// Field starts a field at (x, y) with ^FO. The field is terminated by Data,
+// or with a bare ^FS when the next element begins.
+func (b *LabelBuilder) Field(x, y int) *LabelBuilder {
+	b.field(x, y)
+	return b
+}
+
+// field implements Field, reporting false if the origin, once shifted by
+// Offset, is invalid and nothing was written. The methods adding whole
+// fields then leave out the rest of the field too.
+func (b *LabelBuilder) field(x, y int) bool {
+	x, y = x+b.dx, y+b.dy
+	if !b.checkOrigin(x, y) {
+		return false
+	}
+	b.ensureOpen()
+	b.closeField()
+	fmt.Fprintf(&b.body, "^FO%d,%d", x, y)
+	if b.reverse {
+		b.body.WriteString("^FR")
+		b.reverse = false
+	}
+	b.fieldOpen = true
+	return true
+}
+
+// Data writes the field data of the current field and terminates it.
+func (b *LabelBuilder) Data(data string) *LabelBuilder {
+	b.ensureOpen()
+	data, ok := b.encodeData(data)
+	if !ok {
+		b.closeField()
+		return b
+	}
+	fmt.Fprintf(&b.body, "^FD%s^FS\n", data)
+	b.fieldOpen = false
+	return b
+}
+
+// Offset shifts the origin of every field added afterwards by dx, dy dots,
+// for example to move a batch clear of worn printhead dots. It replaces
+// any earlier offset and is not applied to Raw commands. A field shifted
+// to negative coordinates is an error.
+func (b *LabelBuilder) Offset(dx, dy int) *LabelBuilder {
+	b.dx, b.dy = dx, dy
+	return b
+}
+
+// Raw appends cmd to the current format unchanged.
+func (b *LabelBuilder) Raw(cmd string) *LabelBuilder {
+	b.ensureOpen()
+	b.body.WriteString(cmd)
+	return b
+}
+
+// Text adds a text field at (x, y) in the given font with character height h
+// and width w, all in dots. An empty font prints in the default font of
+// the format, see DefaultFont, and ignores h and w.
+func (b *LabelBuilder) Text(x, y int, font string, h, w int, data string) *LabelBuilder {
+	if !b.field(x, y) {
+		return b
+	}
+	if font != "" {
+		fmt.Fprintf(&b.body, "^A%sN,%d,%d", font, h, w)
+	}
+	return b.Data(data)
+}
+
+// RotatedText is like Text with the text rotated by o. The rotation is
+// given with the font in ^A, so font must not be empty.
+func (b *LabelBuilder) RotatedText(x, y int, font string, o Orientation, h, w int, data string) *LabelBuilder {
+	if font == "" {
+		b.fail(errors.New("invalid rotated text font: must not be empty"))
+		return b
+	}
+	if !b.checkOrientation(o) || !b.field(x, y) {
+		return b
+	}
+	fmt.Fprintf(&b.body, "^A%s%c,%d,%d", font, o, h, w)
+	return b.Data(data)
+}
+
+// maxBlockLines is the most lines a ^FB field block can hold.
+const maxBlockLines = 9999
+
+// TextBlock adds a text field at (x, y) that wraps at word boundaries
+// within a column width dots wide, using ^FB. Text beyond maxLines lines
+// overprints the last line, so choose maxLines for the longest text
+// expected. font, h and w are as in Text.
+func (b *LabelBuilder) TextBlock(x, y int, font string, h, w, width, maxLines int, text string) *LabelBuilder {
+	if width <= 0 {
+		b.fail(fmt.Errorf("invalid text block width %d: must be positive", width))
+		return b
+	}
+	if maxLines < 1 || maxLines > maxBlockLines {
+		b.fail(fmt.Errorf("invalid text block line count %d: must be between 1 and %d", maxLines, maxBlockLines))
+		return b
+	}
+	if !b.field(x, y) {
+		return b
+	}
+	if font != "" {
+		fmt.Fprintf(&b.body, "^A%sN,%d,%d", font, h, w)
+	}
+	fmt.Fprintf(&b.body, "^FB%d,%d,0,L,0", width, maxLines)
+	return b.Data(text)
+}
+
+// Barcode128 adds a Code 128 barcode at (x, y) with the interpretation line
+// printed below it.
+func (b *LabelBuilder) Barcode128(x, y, height int, data string) *LabelBuilder {
+	return b.Code128(x, y, data, WithBarHeight(height))
+}
+
+// SetQuantity makes the printer print n copies of the current format, with
+// ^PQ placed just before its ^XZ.
+func (b *LabelBuilder) SetQuantity(n int) *LabelBuilder {
+	if err := checkQuantity(n); err != nil {
+		b.fail(err)
+		return b
+	}
+	b.ensureOpen()
+	b.quantity = n
+	return b
+}
+
+// Err returns the first error recorded by the builder.
+func (b *LabelBuilder) Err() error {
+	return b.err
+}
+
+// String returns the ZPL for every format in the builder. A format that is
+// still open is closed in the output without modifying the builder.
+func (b *LabelBuilder) String() string {
+	if !b.open && b.done.Len() == 0 {
+		return "^XA\n^XZ\n"
+	}
+	s := b.done.String()
+	if b.open {
+		s += b.format()
+	}
+	return s
+}
+
+// format renders the current, still open format.
+func (b *LabelBuilder) format() string {
+	var s strings.Builder
+	s.WriteString("^XA\n")
+	if b.encoding != 0 {
+		s.WriteString(b.encoding.command() + "\n")
+	}
+	for _, cmd := range b.header {
+		s.WriteString(cmd)
+		s.WriteString("\n")
+	}
+	if b.units != 0 {
+		fmt.Fprintf(&s, "^MU%c\n", b.units)
+	}
+	s.WriteString(b.body.String())
+	if b.fieldOpen {
+		s.WriteString("^FS\n")
+	}
+	if b.units != 0 {
+		s.WriteString("^MUD\n")
+	}
+	if b.quantity > 0 {
+		fmt.Fprintf(&s, "^PQ%d\n", b.quantity)
+	}
+	s.WriteString("^XZ\n")
+	return s.String()
+}
+
+func (b *LabelBuilder) ensureOpen() {
+	if !b.open {
+		b.Start()
+	}
+}
+
+func (b *LabelBuilder) closeField() {
+	if b.fieldOpen {
+		b.body.WriteString("^FS\n")
+		b.fieldOpen = false
+	}
+}
+
+// checkOrigin records an error for negative coordinates.
+func (b *LabelBuilder) checkOrigin(x, y int) bool {
+	if x < 0 || y < 0 {
+		b.fail(fmt.Errorf("invalid field origin %d,%d: coordinates must be non-negative", x, y))
+		return false
+	}
+	return true
+}
+
+// checkOrientation records an error for an unknown field orientation.
+func (b *LabelBuilder) checkOrientation(o Orientation) bool {
+	switch o {
+	case Normal, Rotated90, Inverted, Rotated270:
+		return true
+	}
+	b.fail(fmt.Errorf("invalid orientation %q: must be N, R, I or B", rune(o)))
+	return false
+}
+
+func (b *LabelBuilder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
@@ -0,0 +1,64 @@
+package zpl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOffsetNegativeOriginDropsField(t *testing.T) {
+	tests := []struct {
+		name string
+		add  func(b *LabelBuilder)
+	}{
+		{"Text", func(b *LabelBuilder) { b.Text(5, 5, "0", 30, 30, "Hello") }},
+		{"RotatedText", func(b *LabelBuilder) { b.RotatedText(5, 5, "0", Rotated90, 30, 30, "Hello") }},
+		{"TextBlock", func(b *LabelBuilder) { b.TextBlock(5, 5, "0", 30, 30, 200, 2, "Hello") }},
+		{"Code128", func(b *LabelBuilder) { b.Code128(5, 5, "123") }},
+		{"FontText", func(b *LabelBuilder) { b.FontText(5, 5, "ARIAL", 30, 30, "Hello") }},
+		{"ClockField", func(b *LabelBuilder) { b.ClockField(5, 5, "%H:%M") }},
+		{"Box", func(b *LabelBuilder) { b.Box(5, 5, 50, 50, 2) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewLabel().Offset(-10, 0)
+			tt.add(b)
+			if b.Err() == nil {
+				t.Error("no error for a field shifted to a negative origin")
+			}
+			if got := b.String(); got != "^XA\n^XZ\n" {
+				t.Errorf("label holds a partial field: %q", got)
+			}
+		})
+	}
+}
+
+func TestOffsetShiftsOrigin(t *testing.T) {
+	b := NewLabel().Offset(10, 20).Text(-5, 0, "0", 30, 30, "Hello")
+	if err := b.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); !strings.Contains(got, "^FO5,20^A0N,30,30^FDHello^FS") {
+		t.Errorf("got %q, want the field at 5,20", got)
+	}
+}
+
+func TestEmptyFont(t *testing.T) {
+	b := NewLabel().DefaultFont("0", 30, 0).
+		Text(5, 5, "", 0, 0, "a").
+		TextBlock(5, 50, "", 0, 0, 200, 2, "b")
+	if err := b.Err(); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	if want := "^FO5,5^FDa^FS\n^FO5,50^FB200,2,0,L,0^FDb^FS\n"; !strings.Contains(got, want) {
+		t.Errorf("got %q, want fields without ^A: %q", got, want)
+	}
+
+	b = NewLabel().RotatedText(5, 5, "", Rotated90, 30, 30, "c")
+	if b.Err() == nil {
+		t.Error("no error for rotated text without a font")
+	}
+	if got := b.String(); got != "^XA\n^XZ\n" {
+		t.Errorf("label holds a partial field: %q", got)
+	}
+}
@@ -0,0 +1,30 @@
+package zpl
+
+// Language is a printer command language, as named by the SGD variable
+// device.languages.
+type Language string
+
+// Languages understood by Zebra printers. Mobile units in CPCL mode report
+// LanguageLinePrint.
+const (
+	LanguageZPL       Language = "zpl"
+	LanguageEPL       Language = "epl"
+	LanguageEPLZPL    Language = "epl_zpl"
+	LanguageLinePrint Language = "line_print"
+)
+
+// DetectLanguage asks the printer which command language is active.
+func DetectLanguage(p PrinterConnection) (Language, error) {
+	value, err := GetVar(p, "device.languages")
+	if err != nil {
+		return "", err
+	}
+	return Language(value), nil
+}
+
+// SetLanguage switches the printer to lang. Sending ZPL to a printer in EPL
+// or line print mode prints the commands as text, so mixed fleets should
+// switch to LanguageZPL before printing.
+func SetLanguage(p PrinterConnection, lang Language) error {
+	return SetVar(p, "device.languages", string(lang))
+}
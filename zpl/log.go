@@ -0,0 +1,59 @@
+package zpl
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// discardLogger is used when no logger is configured.
+var discardLogger = slog.New(discardHandler{})
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+func (o options) log() *slog.Logger {
+	if o.logger == nil {
+		return discardLogger
+	}
+	return o.logger
+}
+
+// with returns o with args attached to every record it logs, so each
+// transport can identify itself once.
+func (o options) with(args ...any) options {
+	if o.logger != nil {
+		o.logger = o.logger.With(args...)
+	}
+	return o
+}
+
+// observeSend logs the outcome of a send of n bytes holding labels formats
+// that began at start, reports it to the metrics and counts its labels
+// once it has succeeded. Each send is logged with its job number.
+func (o options) observeSend(start time.Time, n int64, labels int, err error) {
+	dur := time.Since(start)
+	if o.metrics != nil {
+		o.metrics.ObserveSend(int(n), dur, err)
+	}
+	job := o.stats.jobs.Add(1)
+	if err != nil {
+		o.log().Error("send failed", "job", job, "bytes", n, "duration", dur, "err", err)
+		return
+	}
+	o.stats.labels.Add(uint64(labels))
+	o.log().Debug("sent", "job", job, "bytes", n, "labels", labels, "duration", dur)
+}
+
+// logClose records that a printer was closed.
+func (o options) logClose(err error) {
+	if err != nil {
+		o.log().Warn("close failed", "err", err)
+		return
+	}
+	o.log().Info("printer closed")
+}
@@ -0,0 +1,104 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ErrUnknownPrinter is returned by PrinterManager for a name that has not
+// been registered.
+var ErrUnknownPrinter = errors.New("unknown printer")
+
+// PrinterManager routes labels to a fleet of named printers. Sends to the
+// same printer are serialized, so even connections that are not safe for
+// concurrent use can be shared; sends to different printers run in
+// parallel.
+type PrinterManager struct {
+	mu       sync.RWMutex
+	printers map[string]*managedPrinter
+}
+
+// managedPrinter is a registered printer with the lock serializing its
+// sends.
+type managedPrinter struct {
+	mu   sync.Mutex
+	conn PrinterConnection
+}
+
+// NewPrinterManager returns a manager with no printers.
+func NewPrinterManager() *PrinterManager {
+	return &PrinterManager{printers: make(map[string]*managedPrinter)}
+}
+
+// Register adds p under name. The manager takes ownership of p and closes
+// it in Close. Registering a name twice is an error.
+func (m *PrinterManager) Register(name string, p PrinterConnection) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.printers[name]; ok {
+		return fmt.Errorf("printer %q is already registered", name)
+	}
+	m.printers[name] = &managedPrinter{conn: p}
+	return nil
+}
+
+// Unregister removes the printer registered under name and returns it,
+// still open.
+func (m *PrinterManager) Unregister(name string) (PrinterConnection, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mp, ok := m.printers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPrinter, name)
+	}
+	delete(m.printers, name)
+	return mp.conn, nil
+}
+
+// Send sends zpl to the printer registered under name, waiting for any
+// other send to that printer to finish first.
+func (m *PrinterManager) Send(name, zpl string) error {
+	m.mu.RLock()
+	mp, ok := m.printers[name]
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownPrinter, name)
+	}
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	if err := mp.conn.SendZPL(zpl); err != nil {
+		return fmt.Errorf("printer %q: %w", name, err)
+	}
+	return nil
+}
+
+// Printers returns the registered names in sorted order.
+func (m *PrinterManager) Printers() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return slices.Sorted(maps.Keys(m.printers))
+}
+
+// Close closes every registered printer, once its send in progress has
+// finished, and unregisters them all. It returns the errors of every
+// printer that failed to close.
+func (m *PrinterManager) Close() error {
+	m.mu.Lock()
+	printers := m.printers
+	m.printers = make(map[string]*managedPrinter)
+	m.mu.Unlock()
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(printers)) {
+		mp := printers[name]
+		mp.mu.Lock()
+		if err := mp.conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("printer %q: %w", name, err))
+		}
+		mp.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
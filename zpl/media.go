@@ -0,0 +1,80 @@
+package zpl
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Width sets the print width of the current format with ^PW.
+func (b *LabelBuilder) Width(dots int) *LabelBuilder {
+	if dots <= 0 {
+		b.fail(fmt.Errorf("invalid print width %d: must be positive", dots))
+		return b
+	}
+	return b.setup("^PW", fmt.Sprintf("^PW%d", dots))
+}
+
+// Length sets the label length of the current format with ^LL.
+func (b *LabelBuilder) Length(dots int) *LabelBuilder {
+	if dots <= 0 {
+		b.fail(fmt.Errorf("invalid label length %d: must be positive", dots))
+		return b
+	}
+	return b.setup("^LL", fmt.Sprintf("^LL%d", dots))
+}
+
+// Home moves the label home, the origin of every field, to (x, y) with ^LH.
+func (b *LabelBuilder) Home(x, y int) *LabelBuilder {
+	if x < 0 || y < 0 {
+		b.fail(fmt.Errorf("invalid label home %d,%d: coordinates must be non-negative", x, y))
+		return b
+	}
+	return b.setup("^LH", fmt.Sprintf("^LH%d,%d", x, y))
+}
+
+// Rotate180 prints the whole label upside down with ^POI, for stock loaded
+// the other way round. Like the other setup commands it is placed ahead of
+// every field of the format.
+func (b *LabelBuilder) Rotate180() *LabelBuilder {
+	return b.PrintOrientation(Inverted)
+}
+
+// PrintOrientation sets the orientation of the whole label with ^PO, either
+// Normal or Inverted.
+func (b *LabelBuilder) PrintOrientation(o Orientation) *LabelBuilder {
+	if o != Normal && o != Inverted {
+		b.fail(fmt.Errorf("invalid print orientation %q: must be N or I", rune(o)))
+		return b
+	}
+	return b.setup("^PO", fmt.Sprintf("^PO%c", o))
+}
+
+// MediaMM sets the print width and label length from the stock size in
+// millimeters, converted to dots at dpi.
+func (b *LabelBuilder) MediaMM(widthMM, heightMM float64, dpi int) *LabelBuilder {
+	if dpi <= 0 {
+		b.fail(fmt.Errorf("invalid resolution %d dpi", dpi))
+		return b
+	}
+	return b.Width(mmToDots(widthMM, dpi)).Length(mmToDots(heightMM, dpi))
+}
+
+// setup places cmd at the top of the current format, replacing an earlier
+// command with the same prefix.
+func (b *LabelBuilder) setup(prefix, cmd string) *LabelBuilder {
+	b.ensureOpen()
+	for i, c := range b.header {
+		if strings.HasPrefix(c, prefix) {
+			b.header[i] = cmd
+			return b
+		}
+	}
+	b.header = append(b.header, cmd)
+	return b
+}
+
+// mmToDots converts mm to the nearest whole number of dots at dpi.
+func mmToDots(mm float64, dpi int) int {
+	return int(math.Round(mm * float64(dpi) / 25.4))
+}
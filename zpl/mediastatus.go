@@ -0,0 +1,61 @@
+package zpl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MediaStatus is what the printer reports about its media and ribbon
+// supply. Printers leave out what they do not track, such as the ribbon
+// of a direct thermal model; the Has fields tell a missing length from a
+// length of zero. Lengths are in inches.
+type MediaStatus struct {
+	Type string // media.type, such as "gap/notch", "mark" or "continuous"; empty if not reported
+
+	MediaLength    int // media.length, the media left on the roll
+	HasMediaLength bool
+
+	RibbonRemaining    int // ribbon.remaining, the ribbon left on the supply spindle
+	HasRibbonRemaining bool
+}
+
+// GetMediaStatus reads the media and ribbon supply with the SGD variables
+// media.type, media.length and ribbon.remaining. A variable the printer
+// does not support, or answers with no usable value, leaves its fields
+// zero instead of failing the call; only a failed query is an error. It
+// needs a bidirectional connection.
+func GetMediaStatus(p PrinterConnection) (MediaStatus, error) {
+	read := func(name string) (string, error) {
+		v, err := GetVar(p, name)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if v = strings.TrimSpace(v); v == "?" {
+			return "", nil
+		}
+		return v, nil
+	}
+	var s MediaStatus
+	var err error
+	if s.Type, err = read("media.type"); err != nil {
+		return MediaStatus{}, err
+	}
+	length, err := read("media.length")
+	if err != nil {
+		return MediaStatus{}, err
+	}
+	ribbon, err := read("ribbon.remaining")
+	if err != nil {
+		return MediaStatus{}, err
+	}
+	s.MediaLength, s.HasMediaLength = parseSupply(length)
+	s.RibbonRemaining, s.HasRibbonRemaining = parseSupply(ribbon)
+	return s, nil
+}
+
+// parseSupply parses a supply length as parseOdometerLength does,
+// reporting false if v holds none.
+func parseSupply(v string) (int, bool) {
+	n, err := parseOdometerLength(v)
+	return n, err == nil
+}
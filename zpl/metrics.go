@@ -0,0 +1,13 @@
+package zpl
+
+import "time"
+
+// Metrics receives an observation for every send made by a printer created
+// with WithMetrics, such as to feed counters and latency histograms. Give
+// each printer its own Metrics, or one that labels by printer, to tell them
+// apart. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveSend is called after each send with the number of bytes
+	// written, how long the send took and its error, nil on success.
+	ObserveSend(bytes int, dur time.Duration, err error)
+}
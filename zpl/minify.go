@@ -0,0 +1,77 @@
+package zpl
+
+import "strings"
+
+// Minify removes the whitespace between commands of zpl, such as
+// indentation and blank lines, which the printer ignores but which still
+// has to be sent. Field data (^FD, ^FV, ^SN and ^SF) is kept byte for byte,
+// tildes included, since trailing spaces in it are printed.
+//
+// Binary data cannot be told apart from commands without decoding it, so
+// from the first ^GF or ~DY with binary or compressed data on, the rest of
+// zpl is copied unchanged. Minify assumes the default ^ and ~ prefixes.
+func Minify(zpl string) string {
+	start := strings.IndexAny(zpl, "^~")
+	if start < 0 {
+		return strings.TrimSpace(zpl)
+	}
+	var b strings.Builder
+	b.Grow(len(zpl) - start)
+	for i := start; i < len(zpl); {
+		// ^A takes its font as the first parameter character
+		n := 2
+		if i+1 < len(zpl) && (zpl[i+1] == 'A' || zpl[i+1] == 'a') {
+			n = 1
+		}
+		body := min(i+1+n, len(zpl))
+		code := strings.ToUpper(zpl[i+1 : body])
+
+		if isBinaryDownload(zpl[i], code, zpl[body:]) {
+			b.WriteString(zpl[i:])
+			break
+		}
+
+		stop := "^~"
+		data := isFieldData(code)
+		if data {
+			stop = "^"
+		}
+		end := strings.IndexAny(zpl[body:], stop)
+		if end < 0 {
+			end = len(zpl) - body
+		}
+		seg := zpl[i : body+end]
+		if !data {
+			seg = strings.TrimRight(seg, " \t\r\n")
+		}
+		b.WriteString(seg)
+		i = body + end
+	}
+	return b.String()
+}
+
+// isFieldData reports whether the parameters of code are printed data, in
+// which whitespace and tildes are significant.
+func isFieldData(code string) bool {
+	switch code {
+	case "FD", "FV", "SN", "SF":
+		return true
+	}
+	return false
+}
+
+// isBinaryDownload reports whether the ^GF or ~DY command with prefix and
+// code, whose parameters start params, carries binary or compressed data.
+func isBinaryDownload(prefix byte, code, params string) bool {
+	var field int
+	switch {
+	case prefix == '^' && code == "GF":
+		field = 0
+	case prefix == '~' && code == "DY":
+		field = 1
+	default:
+		return false
+	}
+	f := strings.ToUpper(arg(strings.SplitN(params, ",", field+2), field))
+	return f == "B" || f == "C"
+}
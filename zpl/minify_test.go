@@ -0,0 +1,34 @@
+package zpl
+
+import "testing"
+
+func TestMinify(t *testing.T) {
+	tests := []struct {
+		name, zpl, want string
+	}{
+		{"indentation", "^XA\n  ^FO10,10\n  ^A0N,30,30\n  ^FDHi^FS\n^XZ\n", "^XA^FO10,10^A0N,30,30^FDHi^FS^XZ"},
+		{"field data kept", "^XA^FDa ~b  ^FS  ^XZ", "^XA^FDa ~b  ^FS^XZ"},
+		{"lower case", "^xa\n^fdx  ^fs\n^xz", "^xa^fdx  ^fs^xz"},
+		{"text before the first command", "  \n^XA^XZ", "^XA^XZ"},
+		{"no commands", "  hello \n", "hello"},
+		{"binary graphic", "^XA\n^GFB,2,2,1,\x00\n^FS\n^XZ", "^XA^GFB,2,2,1,\x00\n^FS\n^XZ"},
+		{"trailing prefix", "^XA ^", "^XA^"},
+		{"trailing caret A", "^XA^A", "^XA^A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Minify(tt.zpl); got != tt.want {
+				t.Errorf("Minify(%q) = %q, want %q", tt.zpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinifyInvalidUTF8(t *testing.T) {
+	// strings.ToUpper turns each invalid byte into a three byte U+FFFD
+	for _, zpl := range []string{"}}FS~:ÿ", "\x02:^.ÿ", "}}FS~:\xff", "^\xff\xff", "~\xffA"} {
+		if got := Minify(zpl); len(got) > len(zpl) {
+			t.Errorf("Minify(%q) = %q, longer than its input", zpl, got)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package zpl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// MockPrinter is an in-memory PrinterConnection for tests. It records every
+// payload instead of talking to hardware.
+type MockPrinter struct {
+	mu     sync.Mutex
+	sent   []string
+	sends  int
+	failOn int
+	err    error
+	closed bool
+
+	writeDeadline time.Time
+}
+
+// NewMockPrinter returns a MockPrinter that accepts every send.
+func NewMockPrinter() *MockPrinter {
+	return &MockPrinter{}
+}
+
+// FailOn makes the nth send (counting from 1) return err instead of
+// recording its payload.
+func (m *MockPrinter) FailOn(n int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failOn = n
+	m.err = err
+}
+
+// SendZPL records zpl as sent.
+func (m *MockPrinter) SendZPL(zpl string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sends++
+	if m.sends == m.failOn {
+		return m.err
+	}
+	if !m.writeDeadline.IsZero() && !time.Now().Before(m.writeDeadline) {
+		return context.DeadlineExceeded
+	}
+	m.sent = append(m.sent, zpl)
+	return nil
+}
+
+// SendZPLN records zpl as sent and returns its length.
+func (m *MockPrinter) SendZPLN(zpl string) (int, error) {
+	if err := m.SendZPL(zpl); err != nil {
+		return 0, err
+	}
+	return len(zpl), nil
+}
+
+// SendZPLContext records zpl as sent unless ctx is already done.
+func (m *MockPrinter) SendZPLContext(ctx context.Context, zpl string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.SendZPL(zpl)
+}
+
+// SendZPLReader reads r to the end and records it as one payload.
+func (m *MockPrinter) SendZPLReader(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return m.SendZPL(string(data))
+}
+
+// RawSend records data as one payload.
+func (m *MockPrinter) RawSend(data []byte) error {
+	return m.SendZPL(string(data))
+}
+
+// SendRaw records data as one payload and returns its length.
+func (m *MockPrinter) SendRaw(data []byte) (int, error) {
+	return m.SendZPLN(string(data))
+}
+
+// SetDeadline sets the write deadline; the mock has nothing to read.
+func (m *MockPrinter) SetDeadline(t time.Time) error {
+	return m.SetWriteDeadline(t)
+}
+
+// SetWriteDeadline makes sends fail with context.DeadlineExceeded from t
+// on. A zero t clears the deadline.
+func (m *MockPrinter) SetWriteDeadline(t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writeDeadline = t
+	return nil
+}
+
+// Ping fails only once the printer has been closed.
+func (m *MockPrinter) Ping() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return fmt.Errorf("%w: mock printer is closed", ErrNotConnected)
+	}
+	return nil
+}
+
+// Sent returns the payloads recorded so far, in order.
+func (m *MockPrinter) Sent() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.sent...)
+}
+
+// SentCount returns the number of label formats, ended by ^XZ, in the
+// payloads recorded so far.
+func (m *MockPrinter) SentCount() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n uint64
+	for _, zpl := range m.sent {
+		n += uint64(countFormats(zpl))
+	}
+	return n
+}
+
+// Closed reports whether Close has been called.
+func (m *MockPrinter) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// Close marks the printer closed. It never fails.
+func (m *MockPrinter) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	return nil
+}
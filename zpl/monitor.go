@@ -0,0 +1,53 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Monitor polls p with ~HS right away and then every interval, passing
+// each parsed status, or the error that prevented reading it, to cb.
+// Errors do not end the monitor: a printer that stops answering for a
+// while is reported and polled again on the next tick. Polls take turns
+// with sends like any other query and never overlap, so a slow answer
+// delays the next poll instead of piling up behind it.
+//
+// Monitor runs until p is closed or the returned stop function is called.
+// stop may be called more than once, and from cb; the result of a poll in
+// progress is then dropped. p must be able to read responses back.
+func Monitor(p PrinterConnection, interval time.Duration, cb func(HostStatus, error)) (stop func(), err error) {
+	if interval <= 0 {
+		return nil, errors.New("invalid monitor interval: must be positive")
+	}
+	r, ok := asResponder(p)
+	if !ok {
+		return nil, ErrNotBidirectional
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer cancel()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			closed := r.closed()
+			s, err := hostStatus(p)
+			select {
+			case <-ctx.Done():
+				return
+			case <-closed:
+				return
+			default:
+				cb(s, err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-closed:
+				return
+			case <-t.C:
+			}
+		}
+	}()
+	return cancel, nil
+}
@@ -0,0 +1,227 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// DefaultNetworkTimeout bounds dialing and each write of a NetworkPrinter
+// created with NewNetworkPrinter.
+const DefaultNetworkTimeout = 10 * time.Second
+
+// DefaultPort is the raw TCP port Zebra printers accept ZPL on.
+const DefaultPort = 9100
+
+// networkChunkSize is the size of each socket write.
+const networkChunkSize = 32 * 1024
+
+// NetworkPrinter is a printer reached over raw TCP, usually on port 9100.
+// It is a TransportPrinter over the socket and is safe for concurrent use;
+// each send is written as one unit. With WithReconnect, a failed SendZPL
+// is retried on a fresh connection; SendRaw and streams are not.
+type NetworkPrinter struct {
+	*TransportPrinter
+	addr string
+}
+
+// NewNetworkPrinter dials the printer at addr ("host:port") using
+// DefaultNetworkTimeout. A bare host is dialed on DefaultPort. IPv6
+// addresses with a port are written in brackets, as in "[fe80::1]:9100".
+func NewNetworkPrinter(addr string, opts ...Option) (*NetworkPrinter, error) {
+	return NewNetworkPrinterWithTimeout(addr, DefaultNetworkTimeout, opts...)
+}
+
+// NewNetworkPrinterOn dials the printer at host on the given port. An IPv6
+// host may be given with or without brackets.
+func NewNetworkPrinterOn(host string, port int, opts ...Option) (*NetworkPrinter, error) {
+	return NewNetworkPrinter(net.JoinHostPort(unbracket(host), strconv.Itoa(port)), opts...)
+}
+
+// NewNetworkPrinterWithTimeout dials the printer at addr, giving up after
+// timeout. The same timeout is used as the deadline of every socket write;
+// zero disables both.
+func NewNetworkPrinterWithTimeout(addr string, timeout time.Duration, opts ...Option) (*NetworkPrinter, error) {
+	addr = withDefaultPort(addr)
+	o := newOptions(opts).with("transport", "network", "addr", addr)
+	p, err := dialNetworkPrinter(addr, timeout, o, func(timeout time.Duration) (net.Conn, error) {
+		d := net.Dialer{Timeout: timeout, KeepAlive: o.keepAlive}
+		return d.Dial("tcp", addr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := p.opts.connected(p); err != nil {
+		p.Close()
+		return nil, err
+	}
+	p.startHeartbeat()
+	return p, nil
+}
+
+// NewNetworkPrinterWithRetry is like NewNetworkPrinter but makes up to
+// attempts tries at connecting, waiting delay between them, for printers
+// that are still booting when the program starts. Only failures to
+// connect, those wrapping ErrNotConnected, are retried. Once connected the
+// printer behaves as one from NewNetworkPrinter; see WithReconnect for
+// retrying failed sends.
+func NewNetworkPrinterWithRetry(addr string, attempts int, delay time.Duration, opts ...Option) (*NetworkPrinter, error) {
+	if attempts < 1 {
+		return nil, fmt.Errorf("invalid connect attempts %d: must be at least 1", attempts)
+	}
+	log := newOptions(opts).with("transport", "network", "addr", withDefaultPort(addr)).log()
+	for attempt := 1; ; attempt++ {
+		p, err := NewNetworkPrinter(addr, opts...)
+		if err == nil || !errors.Is(err, ErrNotConnected) {
+			return p, err
+		}
+		if attempt == attempts {
+			return nil, fmt.Errorf("gave up after %d attempts: %w", attempts, err)
+		}
+		log.Warn("connect failed, retrying", "attempt", attempt, "delay", delay, "err", err)
+		time.Sleep(delay)
+	}
+}
+
+// dialNetworkPrinter connects to addr with dialer, bounded by timeout
+// unless it is zero, and returns a printer that redials the same way as
+// WithReconnect allows. The setup of opts has not run yet.
+func dialNetworkPrinter(addr string, timeout time.Duration, opts options, dialer func(timeout time.Duration) (net.Conn, error)) (*NetworkPrinter, error) {
+	dial := func() (Transport, error) {
+		conn, err := dialer(timeout)
+		if err != nil {
+			if isTimeout(err) {
+				return nil, fmt.Errorf("%w: timed out connecting to %s after %s: %w", ErrNotConnected, addr, timeout, err)
+			}
+			return nil, fmt.Errorf("%w: failed to connect to %s: %w", ErrNotConnected, addr, err)
+		}
+		return &netTransport{Conn: conn, addr: addr, timeout: timeout}, nil
+	}
+	t, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	p := &NetworkPrinter{TransportPrinter: newTransportPrinter(t, opts, networkChunkSize), addr: addr}
+	p.redial = &redialer{
+		open:    dial,
+		retries: opts.reconnectRetries,
+		delay:   opts.reconnectDelay,
+		retryable: func(err error) bool {
+			return errors.Is(err, ErrNotConnected)
+		},
+	}
+	return p, nil
+}
+
+// startHeartbeat pings the printer every heartbeat interval, if one is
+// configured, until the printer is closed.
+func (p *NetworkPrinter) startHeartbeat() {
+	interval := p.opts.heartbeat
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-p.closed():
+				return
+			case <-t.C:
+				if err := p.Ping(); err != nil {
+					p.opts.log().Warn("heartbeat failed", "err", err)
+				}
+			}
+		}
+	}()
+}
+
+// Flush waits for any send in progress to finish and then confirms that
+// the printer has received everything written so far. A returned write
+// only means the data reached the OS send buffer, so Flush sends ~HI and
+// waits for the answer: the printer reads its input in order, so a reply
+// means every earlier byte has arrived.
+func (p *NetworkPrinter) Flush() error {
+	if _, err := query(p, "~HI", frames(1), responseTimeout); err != nil {
+		return fmt.Errorf("%w: failed to flush: %w", ErrNotConnected, err)
+	}
+	return nil
+}
+
+// Ping sends ~HI (host identification) and waits for the printer to answer.
+func (p *NetworkPrinter) Ping() error {
+	if _, err := query(p, "~HI", frames(1), responseTimeout); err != nil {
+		return fmt.Errorf("%w: no answer to ~HI: %w", ErrNotConnected, err)
+	}
+	return nil
+}
+
+// netTransport is the socket of a NetworkPrinter.
+type netTransport struct {
+	net.Conn
+	addr    string
+	timeout time.Duration // bounds each write, unless zero
+}
+
+// WriteContext writes b with the printer timeout or the deadline of ctx,
+// whichever comes first, as the write deadline. Cancelling ctx aborts the
+// write in progress.
+func (t *netTransport) WriteContext(ctx context.Context, b []byte) (int, error) {
+	t.SetWriteDeadline(t.writeDeadline(ctx))
+
+	// Expire the deadline immediately if ctx is cancelled mid-write
+	stop := context.AfterFunc(ctx, func() {
+		t.SetWriteDeadline(time.Now())
+	})
+	defer stop()
+
+	n, err := t.Conn.Write(b)
+	if err != nil && ctx.Err() == nil && isTimeout(err) {
+		return n, fmt.Errorf("timed out sending to %s: %w", t.addr, err)
+	}
+	return n, err
+}
+
+// writeDeadline returns the deadline for a write starting now: the printer
+// timeout or the deadline of ctx, whichever comes first.
+func (t *netTransport) writeDeadline(ctx context.Context) time.Time {
+	var deadline time.Time
+	if t.timeout > 0 {
+		deadline = time.Now().Add(t.timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	return deadline
+}
+
+// withDefaultPort appends DefaultPort to addr unless it already has a port.
+// Bare IPv6 addresses, with or without brackets, are accepted, and so is
+// an empty port as in "host:".
+func withDefaultPort(addr string) string {
+	port := strconv.Itoa(DefaultPort)
+	if host, p, err := net.SplitHostPort(addr); err == nil {
+		if p == "" {
+			return net.JoinHostPort(host, port)
+		}
+		return addr
+	}
+	return net.JoinHostPort(unbracket(addr), port)
+}
+
+// unbracket removes the brackets around an IPv6 literal such as "[::1]".
+func unbracket(host string) string {
+	if len(host) > 1 && host[0] == '[' && host[len(host)-1] == ']' {
+		return host[1 : len(host)-1]
+	}
+	return host
+}
+
+// isTimeout reports whether err is a network timeout.
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
@@ -0,0 +1,84 @@
+package zpl
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"testing"
+)
+
+func TestWithDefaultPort(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"printer.local", "printer.local:9100"},
+		{"printer.local:6101", "printer.local:6101"},
+		{"printer.local:", "printer.local:9100"},
+		{"10.0.0.5", "10.0.0.5:9100"},
+		{"fe80::1", "[fe80::1]:9100"},
+		{"[fe80::1]", "[fe80::1]:9100"},
+		{"[fe80::1]:6101", "[fe80::1]:6101"},
+		{"[fe80::1]:", "[fe80::1]:9100"},
+		{"fe80::1%eth0", "[fe80::1%eth0]:9100"},
+		{"[fe80::1%eth0]:6101", "[fe80::1%eth0]:6101"},
+	}
+	for _, tt := range tests {
+		if got := withDefaultPort(tt.addr); got != tt.want {
+			t.Errorf("withDefaultPort(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+// listenIPv6 starts a listener on the IPv6 loopback that collects
+// everything sent to it, skipping the test where IPv6 is unavailable.
+func listenIPv6(t *testing.T) (net.Listener, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	received := make(chan string, 1)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer c.Close()
+		data, _ := io.ReadAll(c)
+		received <- string(data)
+	}()
+	return ln, received
+}
+
+func TestNetworkPrinterIPv6(t *testing.T) {
+	port := 0
+	for _, connect := range []func(string) (*NetworkPrinter, error){
+		func(addr string) (*NetworkPrinter, error) { return NewNetworkPrinter(addr) },
+		func(string) (*NetworkPrinter, error) { return NewNetworkPrinterOn("::1", port) },
+		func(string) (*NetworkPrinter, error) { return NewNetworkPrinterOn("[::1]", port) },
+	} {
+		ln, received := listenIPv6(t)
+		port = ln.Addr().(*net.TCPAddr).Port
+		addr := ln.Addr().String()
+		if want := "[::1]:" + strconv.Itoa(port); addr != want {
+			t.Fatalf("listener address %q, want %q", addr, want)
+		}
+
+		p, err := connect(addr)
+		if err != nil {
+			t.Fatalf("connecting to %s: %v", addr, err)
+		}
+		if err := p.SendZPL("^XA^XZ"); err != nil {
+			t.Fatalf("SendZPL: %v", err)
+		}
+		if err := p.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		if got := <-received; got != "^XA^XZ\n" {
+			t.Errorf("printer at %s received %q", addr, got)
+		}
+	}
+}
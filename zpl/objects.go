@@ -0,0 +1,61 @@
+package zpl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ObjectInfo describes a file in printer memory, as listed by ^HW.
+type ObjectInfo struct {
+	Drive     string // drive letter, such as "E"
+	Name      string // name without extension, such as "BRAND"
+	Extension string // extension, such as "TTF" or "GRF"
+	Size      int    // in bytes
+}
+
+// Path returns the object as D:NAME.EXT, as used to reference it in ZPL.
+func (o ObjectInfo) Path() string {
+	return o.Drive + ":" + o.Name + "." + o.Extension
+}
+
+// ListObjects lists the files on memory bank memBank ("R:", "E:", "B:" or
+// "A:") with ^HW. The printer must be able to send data back.
+func ListObjects(p PrinterConnection, memBank string) ([]ObjectInfo, error) {
+	drive := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(memBank)), ":")
+	if len(drive) != 1 || !strings.Contains("REBA", drive) {
+		return nil, fmt.Errorf("invalid memory bank %q: want R:, E:, B: or A:", memBank)
+	}
+	resp, err := query(p, "^XA^HW"+drive+":*.*^XZ", frames(1), responseTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return parseDirectory(resp)
+}
+
+// parseDirectory decodes a ^HW listing. Each file is on a line of its own
+// such as "* E:BRAND.TTF 51234"; header and free space lines start with "-".
+func parseDirectory(resp string) ([]ObjectInfo, error) {
+	var objs []ObjectInfo
+	for _, line := range strings.Split(strings.Trim(resp, stx+etx+"\r\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "*") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "*"))
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%w: directory entry %q", ErrMalformedResponse, line)
+		}
+		drive, file, ok := strings.Cut(fields[0], ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: directory entry %q has no drive", ErrMalformedResponse, line)
+		}
+		name, ext, _ := strings.Cut(file, ".")
+		size, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%w: directory entry %q has no size", ErrMalformedResponse, line)
+		}
+		objs = append(objs, ObjectInfo{Drive: drive, Name: name, Extension: ext, Size: size})
+	}
+	return objs, nil
+}
@@ -0,0 +1,69 @@
+package zpl
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// hwListing is the ^HW example from the ZPL programming guide, as framed
+// by the printer.
+const hwListing = "\x02\r\n- DIR R:*.*\r\n*R:ARIALN1.FNT 49140\r\n*R:ARIALN2.FNT 49140\r\n" +
+	"*R:ZEBRA.GRF 8420\r\n-794292 bytes free\r\nR:RAM\r\n\x03"
+
+func TestParseDirectory(t *testing.T) {
+	tests := []struct {
+		name string
+		resp string
+		want []ObjectInfo
+	}{
+		{"programming guide", hwListing, []ObjectInfo{
+			{Drive: "R", Name: "ARIALN1", Extension: "FNT", Size: 49140},
+			{Drive: "R", Name: "ARIALN2", Extension: "FNT", Size: 49140},
+			{Drive: "R", Name: "ZEBRA", Extension: "GRF", Size: 8420},
+		}},
+		{"spaced entries", "\x02- DIR E:*.*\n* E:BRAND.TTF   51234\n* E:LOGO.GRF 1024 \n- 6291456 bytes free E:ONBOARD FLASH\n\x03", []ObjectInfo{
+			{Drive: "E", Name: "BRAND", Extension: "TTF", Size: 51234},
+			{Drive: "E", Name: "LOGO", Extension: "GRF", Size: 1024},
+		}},
+		{"no extension", "\x02* B:README 10\x03", []ObjectInfo{
+			{Drive: "B", Name: "README", Size: 10},
+		}},
+		{"empty drive", "\x02- DIR B:*.*\r\n- 0 bytes free B:CARD\r\n\x03", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDirectory(tt.resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseDirectory(%q) =\n%+v\nwant\n%+v", tt.resp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDirectoryMalformed(t *testing.T) {
+	tests := []struct {
+		name, resp string
+	}{
+		{"no size", "\x02*R:ZEBRA.GRF\x03"},
+		{"bad size", "\x02*R:ZEBRA.GRF 8k\x03"},
+		{"no drive", "\x02*ZEBRA.GRF 8420\x03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseDirectory(tt.resp); !errors.Is(err, ErrMalformedResponse) {
+				t.Errorf("parseDirectory(%q) = %v, want ErrMalformedResponse", tt.resp, err)
+			}
+		})
+	}
+}
+
+func TestObjectInfoPath(t *testing.T) {
+	o := ObjectInfo{Drive: "E", Name: "BRAND", Extension: "TTF"}
+	if got := o.Path(); got != "E:BRAND.TTF" {
+		t.Errorf("Path() = %q, want E:BRAND.TTF", got)
+	}
+}
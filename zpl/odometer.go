@@ -0,0 +1,67 @@
+package zpl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OdometerInfo is the printer's usage counters. Distances are in inches.
+type OdometerInfo struct {
+	TotalLabels int // labels printed over the printer's life
+	UserLabels  int // labels printed since the user counter was last reset
+
+	// HeadCleanInches is the media length printed since the printhead was
+	// last cleaned, and HeadNewInches since it was replaced. Compare them
+	// to the cleaning and replacement intervals of the printhead in use.
+	HeadCleanInches int
+	HeadNewInches   int
+}
+
+// Odometer reads the usage counters with the SGD odometer variables, the
+// same counters the ~WQOD report prints. It needs a bidirectional
+// connection.
+func Odometer(p PrinterConnection) (OdometerInfo, error) {
+	var info OdometerInfo
+	counters := []struct {
+		name  string
+		dst   *int
+		parse func(string) (int, error)
+	}{
+		{"odometer.total_label_count", &info.TotalLabels, parseOdometerCount},
+		{"odometer.user_label_count", &info.UserLabels, parseOdometerCount},
+		{"odometer.headclean", &info.HeadCleanInches, parseOdometerLength},
+		{"odometer.headnew", &info.HeadNewInches, parseOdometerLength},
+	}
+	for _, c := range counters {
+		v, err := GetVar(p, c.name)
+		if err != nil {
+			return OdometerInfo{}, fmt.Errorf("failed to read %s: %w", c.name, err)
+		}
+		if *c.dst, err = c.parse(v); err != nil {
+			return OdometerInfo{}, fmt.Errorf("%w: %s %q", ErrMalformedResponse, c.name, v)
+		}
+	}
+	return info, nil
+}
+
+func parseOdometerCount(v string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(v))
+}
+
+// parseOdometerLength parses a head odometer such as "1329 INCHES, 3375
+// CENTIMETERS", or a bare number of inches, into inches.
+func parseOdometerLength(v string) (int, error) {
+	fields := strings.Fields(strings.ReplaceAll(v, ",", " "))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty length")
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, err
+	}
+	if len(fields) > 1 && strings.HasPrefix(strings.ToUpper(fields[1]), "CENTIM") {
+		return int(float64(n)/2.54 + 0.5), nil
+	}
+	return n, nil
+}
@@ -0,0 +1,298 @@
+package zpl
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Option configures a printer at construction time. Options that do not
+// apply to a transport are ignored by it.
+type Option func(*options)
+
+type options struct {
+	reconnectRetries int
+	reconnectDelay   time.Duration
+	keepAlive        time.Duration
+	heartbeat        time.Duration
+	strict           bool
+	outEndpoint      int
+	usbConfig        int
+	usbInterface     int
+	usbAltSetting    int
+	noAutoDetach     bool
+	usbChunkSize     int
+	usbWriteTimeout  time.Duration
+	language         Language
+	encoding         Encoding
+	prolog           string
+	logger           *slog.Logger
+	metrics          Metrics
+	stats            *sendStats
+}
+
+func newOptions(opts []Option) options {
+	o := options{stats: new(sendStats)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// connected runs the setup requested by the options on a freshly opened
+// printer.
+func (o options) connected(p PrinterConnection) error {
+	if err := o.setup(p.SendZPL); err != nil {
+		return err
+	}
+	o.log().Info("printer connected")
+	return nil
+}
+
+// setup sends, with send, the commands the options ask for on every new
+// connection to the printer.
+func (o options) setup(send func(zpl string) error) error {
+	if o.language == "" {
+		return nil
+	}
+	cmd, err := setVarCommand("device.languages", string(o.language))
+	if err == nil {
+		err = send(cmd)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to switch printer to %s: %w", o.language, err)
+	}
+	return nil
+}
+
+// prepare splices in the prolog, transcodes zpl to the configured
+// encoding, validates it when strict mode is on and appends the trailing
+// newline the printer expects.
+func (o options) prepare(zpl string) (string, error) {
+	if o.prolog != "" {
+		var err error
+		if zpl, err = withProlog(zpl, o.prolog); err != nil {
+			return "", err
+		}
+	}
+	if o.encoding != 0 {
+		var err error
+		if zpl, err = withEncoding(zpl, o.encoding); err != nil {
+			return "", err
+		}
+	}
+	if o.strict {
+		if err := ValidateZPL(zpl); err != nil {
+			return "", err
+		}
+	}
+	return withNewline(zpl), nil
+}
+
+// WithStrictValidation makes every send check its payload with ValidateZPL
+// and refuse to send it if the check fails, so that a fragment missing its
+// ^XA or ^XZ fails with ErrIncompleteFormat instead of stalling the
+// printer. Payloads of control commands only, such as ~HS, pass. SendBatch
+// checks every label; other streams, SendRaw and RawSend are not checked.
+func WithStrictValidation() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithReconnect makes a NetworkPrinter re-dial and resend when a write fails,
+// up to maxRetries times, waiting baseDelay before the first retry and
+// doubling the wait before each following one.
+func WithReconnect(maxRetries int, baseDelay time.Duration) Option {
+	return func(o *options) {
+		o.reconnectRetries = maxRetries
+		o.reconnectDelay = baseDelay
+	}
+}
+
+// WithKeepAlive sets the TCP keepalive period of a NetworkPrinter's
+// connections, so NAT gateways and firewalls that drop idle connections
+// keep them open. Zero keeps Go's default of 15 seconds; a negative period
+// turns keepalive off.
+func WithKeepAlive(period time.Duration) Option {
+	return func(o *options) {
+		o.keepAlive = period
+	}
+}
+
+// WithHeartbeat makes a NetworkPrinter or BluetoothPrinter send ~HI every
+// interval and read the answer, keeping the path to the printer warm and
+// logging a warning when it stops answering. It runs until Close.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(o *options) {
+		o.heartbeat = interval
+	}
+}
+
+// WithOutEndpoint makes a USBPrinter send on OUT endpoint number ep instead
+// of the lowest numbered one.
+func WithOutEndpoint(ep int) Option {
+	return func(o *options) {
+		o.outEndpoint = ep
+	}
+}
+
+// WithUSBConfig makes a USBPrinter use configuration number cfg instead of
+// the device's active one, for composite devices that expose the printer
+// on another configuration.
+func WithUSBConfig(cfg int) Option {
+	return func(o *options) {
+		o.usbConfig = cfg
+	}
+}
+
+// WithUSBInterface makes a USBPrinter claim interface number intf with
+// alternate setting alt instead of interface 0, setting 0.
+func WithUSBInterface(intf, alt int) Option {
+	return func(o *options) {
+		o.usbInterface = intf
+		o.usbAltSetting = alt
+	}
+}
+
+// WithMetrics makes the printer report every send to m.
+func WithMetrics(m Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithAutoDetach controls whether a USBPrinter asks libusb to detach the
+// kernel printer driver while it holds the interface. It is on by default;
+// turn it off when udev rules or driver blacklisting already keep the kernel
+// driver away.
+func WithAutoDetach(enabled bool) Option {
+	return func(o *options) {
+		o.noAutoDetach = !enabled
+	}
+}
+
+// WithUSBChunkSize makes a USBPrinter split writes into bulk transfers of
+// at most n bytes instead of 16 KB.
+func WithUSBChunkSize(n int) Option {
+	return func(o *options) {
+		o.usbChunkSize = n
+	}
+}
+
+// WithUSBWriteTimeout makes a USBPrinter fail a bulk transfer that has not
+// completed within d. By default transfers wait indefinitely.
+func WithUSBWriteTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.usbWriteTimeout = d
+	}
+}
+
+// WithLanguage switches the printer to lang as soon as it is opened, so
+// printers left in EPL or line print mode do not print ZPL as text.
+func WithLanguage(lang Language) Option {
+	return func(o *options) {
+		o.language = lang
+	}
+}
+
+// WithEncoding makes every send select e with ^CI after each ^XA and
+// transcode the payload to it, unless the payload already contains a ^CI.
+// A payload with characters e cannot represent is refused. SendBatch
+// applies it to every label; other streams, SendRaw and RawSend are sent
+// unchanged.
+func WithEncoding(e Encoding) Option {
+	return func(o *options) {
+		o.encoding = e
+	}
+}
+
+// WithProlog makes every send insert prolog, format commands such as
+// "^CI28^PW812^LL1218", right after each ^XA, so every label format starts
+// with the same setup. Together with WithEncoding, the ^CI comes first and
+// the prolog follows it; a prolog with a ^CI of its own turns WithEncoding
+// off, as any payload with a ^CI does. A prolog with its own ^XA or ^XZ
+// fails every send. SendBatch applies it to every label; other streams,
+// SendRaw and RawSend are sent unchanged.
+func WithProlog(prolog string) Option {
+	return func(o *options) {
+		o.prolog = prolog
+	}
+}
+
+// withProlog inserts prolog after every ^XA of zpl.
+func withProlog(zpl, prolog string) (string, error) {
+	if p := strings.ToUpper(prolog); strings.Contains(p, "^XA") || strings.Contains(p, "^XZ") {
+		return "", fmt.Errorf("invalid prolog %q: must not contain ^XA or ^XZ", prolog)
+	}
+	return insertAfterStart(zpl, prolog), nil
+}
+
+// insertAfterStart inserts s after every ^XA of zpl, in either case.
+func insertAfterStart(zpl, s string) string {
+	var b strings.Builder
+	last := 0
+	for i := indexFold(zpl, "^XA", 0); i >= 0; i = indexFold(zpl, "^XA", last) {
+		b.WriteString(zpl[last : i+3])
+		b.WriteString(s)
+		last = i + 3
+	}
+	if last == 0 {
+		return zpl
+	}
+	b.WriteString(zpl[last:])
+	return b.String()
+}
+
+// indexFold returns the index of the first sub in s at or after from, or
+// -1. ASCII letters match in either case and all other bytes exactly, so
+// unlike searching an upper-cased copy the index is valid in s even when
+// it holds non-ASCII or invalid UTF-8 field data.
+func indexFold[T string | []byte](s T, sub string, from int) int {
+	for i := from; i+len(sub) <= len(s); i++ {
+		if hasFoldAt(s, i, sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastIndexFold is like indexFold but returns the index of the last sub.
+func lastIndexFold[T string | []byte](s T, sub string) int {
+	for i := len(s) - len(sub); i >= 0; i-- {
+		if hasFoldAt(s, i, sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasFoldAt reports whether sub occurs in s at i, comparing as indexFold.
+func hasFoldAt[T string | []byte](s T, i int, sub string) bool {
+	if i < 0 || i+len(sub) > len(s) {
+		return false
+	}
+	for j := 0; j < len(sub); j++ {
+		if lowerASCII(s[i+j]) != lowerASCII(sub[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// WithLogger makes the printer log connects, sends, reconnect attempts and
+// closes to l, with byte counts and durations. Successful sends are logged
+// at debug level. Without it nothing is logged.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
@@ -0,0 +1,48 @@
+package zpl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrepareProlog(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		zpl  string
+		want string
+	}{
+		{"prolog", []Option{WithProlog("^PW812")}, "^XA^FDa^FS^XZ", "^XA^PW812^FDa^FS^XZ\n"},
+		{"lower case start", []Option{WithProlog("^PW812")}, "^xa^fda^fs^xz", "^xa^PW812^fda^fs^xz\n"},
+		{"every format", []Option{WithProlog("^PW812")}, "^XA^XZ^xa^xz", "^XA^PW812^XZ^xa^PW812^xz\n"},
+		{"no format", []Option{WithProlog("^PW812")}, "~HS", "~HS\n"},
+		{"encoding first", []Option{WithProlog("^PW812"), WithEncoding(EncodingLatin1)}, "^xa^FDé^FS^XZ", "^xa^CI27^PW812^FD\xe9^FS^XZ\n"},
+		{"option order", []Option{WithEncoding(EncodingLatin1), WithProlog("^PW812")}, "^XA^FDé^FS^XZ", "^XA^CI27^PW812^FD\xe9^FS^XZ\n"},
+		{"prolog selects encoding", []Option{WithProlog("^CI28"), WithEncoding(EncodingLatin1)}, "^XA^FDé^FS^XZ", "^XA^CI28^FDé^FS^XZ\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newOptions(tt.opts).prepare(tt.zpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("prepare(%q) = %q, want %q", tt.zpl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareInvalidProlog(t *testing.T) {
+	for _, prolog := range []string{"^XA^PW812", "^PW812^xz"} {
+		if _, err := newOptions([]Option{WithProlog(prolog)}).prepare("^XA^XZ"); err == nil {
+			t.Errorf("WithProlog(%q): no error", prolog)
+		}
+	}
+	// Strict validation sees the payload as it will be sent
+	_, err := newOptions([]Option{WithProlog("^FDa"), WithStrictValidation()}).prepare("^XA^XZ")
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("strict validation of a broken prolog: got %v, want a *ValidationError", err)
+	}
+}
@@ -0,0 +1,156 @@
+package zpl
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Command is one ZPL command as split by ParseZPL.
+type Command struct {
+	Prefix byte     // '^' for format commands, '~' for control commands
+	Code   string   // upper case command code, such as "FO", or "A" for ^A
+	Params []string // comma separated parameters, nil if there are none
+	Data   string   // field data, comment text or download data, if any
+	Offset int      // byte offset of the prefix in the parsed string
+}
+
+// String renders c back to ZPL, without the whitespace that followed it.
+func (c Command) String() string {
+	s := string(c.Prefix) + c.Code + strings.Join(c.Params, ",")
+	if c.Params != nil && c.Data != "" {
+		s += ","
+	}
+	return s + c.Data
+}
+
+// download describes the parameters of a download command, which precede
+// its data. The data may contain commas of its own.
+type download struct {
+	params int  // number of parameters before the data
+	format int  // index of the A, B or C data format, -1 for none
+	count  int  // index of the byte count, -1 for none
+	hex    bool // uncompressed data is written as two hex digits per byte
+}
+
+// downloads are the download commands by prefix and code.
+var downloads = map[string]download{
+	"^GF": {params: 4, format: 0, count: 1},             // ^GFa,b,c,d,data
+	"~DY": {params: 5, format: 1, count: 3},             // ~DYd:o,f,x,t,w,data
+	"~DG": {params: 3, format: -1, count: 1, hex: true}, // ~DGd:o.x,t,w,data
+	"~DB": {params: 8, format: -1, count: -1},           // ~DBd:o.x,a,h,w,base,space,#char,©,data
+}
+
+// ParseZPL splits zpl into its commands. Parameters are split at commas;
+// the field data of ^FD and ^FV and the text of ^FX comments run up to the
+// next caret, commas and tildes included, and are returned whole in Data.
+// So is the data of ^GF, ~DB, ~DG and ~DY downloads. Binary and
+// compressed ^GF and ~DY data, and uncompressed ~DG hex, are measured by
+// the byte count in their parameters; other download data runs up to the
+// next command.
+//
+// ^A takes its font as the first parameter character, so ^A0N,30,30 has
+// Code "A" and Params "0N", "30", "30". Text outside commands is dropped.
+// Prefix changes made with ^CC or ~CC and ^CT or ~CT are followed: the
+// commands after them are found by their new prefix but still reported
+// with Prefix '^' or '~', so String writes them with the default ones.
+func ParseZPL(zpl string) []Command {
+	var cmds []Command
+	format, control := byte('^'), byte('~')
+	prefixes := "^~"
+	for i := strings.IndexAny(zpl, prefixes); i >= 0 && i < len(zpl); {
+		start := i
+		n := 2
+		if i+1 < len(zpl) && (zpl[i+1] == 'A' || zpl[i+1] == 'a') {
+			n = 1
+		}
+		body := min(i+1+n, len(zpl))
+		c := Command{Prefix: '^', Code: strings.ToUpper(zpl[i+1 : body]), Offset: start}
+		if zpl[i] == control {
+			c.Prefix = '~'
+		}
+
+		var end int
+		if c.Prefix == '^' && (c.Code == "FD" || c.Code == "FV" || c.Code == "FX") {
+			end = nextIndex(zpl, body, string(format))
+			c.Data = zpl[body:end]
+		} else if (c.Code == "CC" || c.Code == "CT") && body < len(zpl) {
+			// The new prefix is the one character after the code
+			end = body + 1
+			c.Params = []string{zpl[body:end]}
+			if c.Code == "CC" {
+				format = zpl[body]
+			} else {
+				control = zpl[body]
+			}
+			prefixes = string([]byte{format, control})
+		} else if d, ok := downloads[string(c.Prefix)+c.Code]; ok && countParams(zpl[body:], d.params, prefixes) {
+			rest := zpl[body:]
+			c.Params = strings.SplitN(rest, ",", d.params+1)[:d.params]
+			dataStart := body + len(strings.Join(c.Params, ",")) + 1
+			end = d.end(zpl, dataStart, c.Params, prefixes)
+			c.Data = zpl[dataStart:end]
+		} else {
+			end = nextIndex(zpl, body, prefixes)
+			if params := strings.TrimSpace(zpl[body:end]); params != "" {
+				c.Params = strings.Split(params, ",")
+			}
+		}
+		cmds = append(cmds, c)
+		if i = end; i < len(zpl) && zpl[i] != format && zpl[i] != control {
+			i = nextIndex(zpl, i, prefixes)
+		}
+	}
+	return cmds
+}
+
+// nextIndex returns the index of the first of chars in s at or after from,
+// or len(s).
+func nextIndex(s string, from int, chars string) int {
+	if i := strings.IndexAny(s[from:], chars); i >= 0 {
+		return from + i
+	}
+	return len(s)
+}
+
+// countParams reports whether params holds at least n commas before the
+// next command, which starts with one of prefixes.
+func countParams(params string, n int, prefixes string) bool {
+	if end := strings.IndexAny(params, prefixes); end >= 0 {
+		params = params[:end]
+	}
+	return strings.Count(params, ",") >= n
+}
+
+// end returns where the data of a download with params, starting at
+// start in zpl, ends: after the byte count for binary, compressed and
+// uncompressed hex data, at the next command, which starts with one of
+// prefixes, otherwise.
+func (d download) end(zpl string, start int, params []string, prefixes string) int {
+	next := nextIndex(zpl, start, prefixes)
+	if d.count < 0 {
+		return next
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(params[d.count]))
+	if d.format >= 0 {
+		if f := strings.ToUpper(strings.TrimSpace(params[d.format])); f == "B" || f == "C" {
+			if err != nil || n < 0 {
+				return len(zpl)
+			}
+			return min(start+n, len(zpl))
+		}
+	}
+	if d.hex && err == nil && n >= 0 && start+2*n <= next && isHex(zpl[start:start+2*n]) {
+		return start + 2*n
+	}
+	return next
+}
+
+// isHex reports whether s holds only hexadecimal digits.
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !strings.ContainsRune("0123456789ABCDEFabcdef", rune(s[i])) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+package zpl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseZPL(t *testing.T) {
+	tests := []struct {
+		name string
+		zpl  string
+		want []Command
+	}{
+		{"fields", "^XA\n^FO10,20^A0N,30,30^FDa,b~c^FS\n^XZ", []Command{
+			{Prefix: '^', Code: "XA", Offset: 0},
+			{Prefix: '^', Code: "FO", Params: []string{"10", "20"}, Offset: 4},
+			{Prefix: '^', Code: "A", Params: []string{"0N", "30", "30"}, Offset: 12},
+			{Prefix: '^', Code: "FD", Data: "a,b~c", Offset: 22},
+			{Prefix: '^', Code: "FS", Offset: 30},
+			{Prefix: '^', Code: "XZ", Offset: 34},
+		}},
+		{"lower case", "^xa~hs", []Command{
+			{Prefix: '^', Code: "XA", Offset: 0},
+			{Prefix: '~', Code: "HS", Offset: 3},
+		}},
+		{"format prefix changed", "^XA^CC+\n+FO10,10+FDx^y+FS+XZ", []Command{
+			{Prefix: '^', Code: "XA", Offset: 0},
+			{Prefix: '^', Code: "CC", Params: []string{"+"}, Offset: 3},
+			{Prefix: '^', Code: "FO", Params: []string{"10", "10"}, Offset: 8},
+			{Prefix: '^', Code: "FD", Data: "x^y", Offset: 16},
+			{Prefix: '^', Code: "FS", Offset: 22},
+			{Prefix: '^', Code: "XZ", Offset: 25},
+		}},
+		{"format prefix changed by control command", "~CC+\n+XA+XZ", []Command{
+			{Prefix: '~', Code: "CC", Params: []string{"+"}, Offset: 0},
+			{Prefix: '^', Code: "XA", Offset: 5},
+			{Prefix: '^', Code: "XZ", Offset: 8},
+		}},
+		{"control prefix changed", "~CT#\n#HS~XA", []Command{
+			{Prefix: '~', Code: "CT", Params: []string{"#"}, Offset: 0},
+			{Prefix: '~', Code: "HS", Params: []string{"~XA"}, Offset: 5},
+		}},
+		{"prefix change at the end", "^XA^CC", []Command{
+			{Prefix: '^', Code: "XA", Offset: 0},
+			{Prefix: '^', Code: "CC", Offset: 3},
+		}},
+		{"invalid UTF-8", "^FD\xff\xfe^FS^\xffA^XZ", []Command{
+			{Prefix: '^', Code: "FD", Data: "\xff\xfe", Offset: 0},
+			{Prefix: '^', Code: "FS", Offset: 5},
+			{Prefix: '^', Code: "�A", Offset: 8},
+			{Prefix: '^', Code: "XZ", Offset: 11},
+		}},
+		{"truncated", "^XA^F", []Command{
+			{Prefix: '^', Code: "XA", Offset: 0},
+			{Prefix: '^', Code: "F", Offset: 3},
+		}},
+		{"binary graphic", "^GFB,4,4,1,^FO1^FO5,5", []Command{
+			{Prefix: '^', Code: "GF", Params: []string{"B", "4", "4", "1"}, Data: "^FO1", Offset: 0},
+			{Prefix: '^', Code: "FO", Params: []string{"5", "5"}, Offset: 15},
+		}},
+		{"binary font", "~DYR:F,B,T,3,,^XA^XZ", []Command{
+			{Prefix: '~', Code: "DY", Params: []string{"R:F", "B", "T", "3", ""}, Data: "^XA", Offset: 0},
+			{Prefix: '^', Code: "XZ", Offset: 17},
+		}},
+		{"compressed font with carets", "~DYR:F,C,T,3,,^~,^XZ", []Command{
+			{Prefix: '~', Code: "DY", Params: []string{"R:F", "C", "T", "3", ""}, Data: "^~,", Offset: 0},
+			{Prefix: '^', Code: "XZ", Offset: 17},
+		}},
+		{"font named B", "~DYB,A,T,2,,41^XA", []Command{
+			{Prefix: '~', Code: "DY", Params: []string{"B", "A", "T", "2", ""}, Data: "41", Offset: 0},
+			{Prefix: '^', Code: "XA", Offset: 14},
+		}},
+		{"hex graphic", "~DGR:A.GRF,2,1,FF00\n^XA", []Command{
+			{Prefix: '~', Code: "DG", Params: []string{"R:A.GRF", "2", "1"}, Data: "FF00", Offset: 0},
+			{Prefix: '^', Code: "XA", Offset: 20},
+		}},
+		{"graphic named B", "~DGB,2,1,FF00^XA", []Command{
+			{Prefix: '~', Code: "DG", Params: []string{"B", "2", "1"}, Data: "FF00", Offset: 0},
+			{Prefix: '^', Code: "XA", Offset: 13},
+		}},
+		{"compressed graphic", "~DGR:A.GRF,4,2,:Z64:eJxjYGBgAAAABAAB:5A5A\n^XA", []Command{
+			{Prefix: '~', Code: "DG", Params: []string{"R:A.GRF", "4", "2"}, Data: ":Z64:eJxjYGBgAAAABAAB:5A5A\n", Offset: 0},
+			{Prefix: '^', Code: "XA", Offset: 42},
+		}},
+		{"bitmap font named C", "~DBC,N,10,10,8,2,1,ACME,#0041.1.1.1.1.1.FF^XA", []Command{
+			{Prefix: '~', Code: "DB", Params: []string{"C", "N", "10", "10", "8", "2", "1", "ACME"}, Data: "#0041.1.1.1.1.1.FF", Offset: 0},
+			{Prefix: '^', Code: "XA", Offset: 42},
+		}},
+		{"text outside commands", "hello ^XA", []Command{
+			{Prefix: '^', Code: "XA", Offset: 6},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseZPL(tt.zpl); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseZPL(%q) =\n%#v\nwant\n%#v", tt.zpl, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// poolCheckIdle is how long a pooled connection may sit idle before
+// Acquire pings it, rather than trusting it, before handing it out.
+const poolCheckIdle = 30 * time.Second
+
+// ErrPoolClosed is returned by Acquire and Send after the pool has been
+// closed.
+var ErrPoolClosed = errors.New("printer pool closed")
+
+// NetworkPrinterPool shares a bounded set of connections to one network
+// printer among concurrent senders, such as the handlers of a print
+// gateway. Connections are opened on demand and reused; a connection that
+// has been idle for a while is pinged first and replaced if it is dead.
+type NetworkPrinterPool struct {
+	addr  string
+	opts  []Option
+	slots chan struct{} // one token per connection open or allowed
+
+	mu     sync.Mutex
+	idle   []pooledPrinter
+	closed bool
+}
+
+// pooledPrinter is an idle connection and when it was returned.
+type pooledPrinter struct {
+	conn  *NetworkPrinter
+	since time.Time
+}
+
+// NewNetworkPrinterPool returns a pool of up to size connections to addr,
+// opened with opts. No connection is opened until the first Acquire.
+func NewNetworkPrinterPool(addr string, size int, opts ...Option) (*NetworkPrinterPool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid pool size %d: must be positive", size)
+	}
+	return &NetworkPrinterPool{
+		addr:  addr,
+		opts:  opts,
+		slots: make(chan struct{}, size),
+	}, nil
+}
+
+// Acquire hands out a connection for the caller's exclusive use, waiting
+// while all of them are in use until ctx is done. Return it with Release.
+func (pl *NetworkPrinterPool) Acquire(ctx context.Context) (*NetworkPrinter, error) {
+	select {
+	case pl.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	for {
+		conn, since, err := pl.takeIdle()
+		if err != nil {
+			<-pl.slots
+			return nil, err
+		}
+		if conn == nil {
+			break
+		}
+		if time.Since(since) < poolCheckIdle || conn.Ping() == nil {
+			return conn, nil
+		}
+		conn.opts.log().Debug("discarding dead pooled connection")
+		conn.Close()
+	}
+	conn, err := NewNetworkPrinter(pl.addr, pl.opts...)
+	if err != nil {
+		<-pl.slots
+		return nil, err
+	}
+	return conn, nil
+}
+
+// takeIdle pops the most recently returned idle connection, or returns nil
+// if there is none.
+func (pl *NetworkPrinterPool) takeIdle() (*NetworkPrinter, time.Time, error) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+	if pl.closed {
+		return nil, time.Time{}, ErrPoolClosed
+	}
+	if len(pl.idle) == 0 {
+		return nil, time.Time{}, nil
+	}
+	last := pl.idle[len(pl.idle)-1]
+	pl.idle = pl.idle[:len(pl.idle)-1]
+	return last.conn, last.since, nil
+}
+
+// Release returns a connection handed out by Acquire to the pool. Pass a
+// connection that failed with ErrNotConnected, or whose send failed part
+// way, to Discard instead.
+func (pl *NetworkPrinterPool) Release(conn *NetworkPrinter) {
+	pl.mu.Lock()
+	if pl.closed {
+		pl.mu.Unlock()
+		conn.Close()
+	} else {
+		pl.idle = append(pl.idle, pooledPrinter{conn: conn, since: time.Now()})
+		pl.mu.Unlock()
+	}
+	<-pl.slots
+}
+
+// Discard closes a connection handed out by Acquire instead of returning
+// it, freeing its place in the pool for a new one.
+func (pl *NetworkPrinterPool) Discard(conn *NetworkPrinter) {
+	conn.Close()
+	<-pl.slots
+}
+
+// Send sends zpl over a pooled connection. A connection whose send failed
+// after writing part of zpl, such as one cancelled through ctx, is
+// discarded, since the next label sent on it would be appended to the
+// partial one.
+func (pl *NetworkPrinterPool) Send(ctx context.Context, zpl string) error {
+	conn, err := pl.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	release := conn.acquire()
+	n, err := conn.send(ctx, zpl)
+	release()
+	if err != nil && (n > 0 || errors.Is(err, ErrNotConnected)) {
+		pl.Discard(conn)
+	} else {
+		pl.Release(conn)
+	}
+	return err
+}
+
+// Close closes the idle connections and makes the pool refuse further
+// Acquire calls. Connections still in use are closed when released.
+func (pl *NetworkPrinterPool) Close() error {
+	pl.mu.Lock()
+	idle := pl.idle
+	pl.idle = nil
+	pl.closed = true
+	pl.mu.Unlock()
+
+	var errs []error
+	for _, p := range idle {
+		if err := p.conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,181 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestPool(t *testing.T, size int, opts ...Option) (*NetworkPrinterPool, *TestServer) {
+	t.Helper()
+	s, err := NewTestServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	pl, err := NewNetworkPrinterPool(s.Addr(), size, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pl.Close() })
+	return pl, s
+}
+
+func TestNetworkPrinterPoolReuse(t *testing.T) {
+	pl, _ := newTestPool(t, 2)
+	ctx := context.Background()
+	first, err := pl.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := pl.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatal("two Acquire calls got the same connection")
+	}
+	pl.Release(first)
+	again, err := pl.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Error("Acquire opened a new connection instead of reusing the released one")
+	}
+	pl.Release(again)
+	pl.Release(second)
+}
+
+func TestNetworkPrinterPoolFull(t *testing.T) {
+	pl, _ := newTestPool(t, 1)
+	conn, err := pl.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := pl.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire on a full pool = %v, want context.DeadlineExceeded", err)
+	}
+	pl.Release(conn)
+}
+
+func TestNetworkPrinterPoolSend(t *testing.T) {
+	pl, s := newTestPool(t, 2)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := pl.Send(context.Background(), "^XA^FDpooled^FS^XZ"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(pl.idle); n > 2 {
+		t.Errorf("%d idle connections in a pool of 2", n)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.Formats()) < 8 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := len(s.Formats()); n != 8 {
+		t.Errorf("server got %d formats, want 8", n)
+	}
+}
+
+func TestNetworkPrinterPoolKeepsConnectionOnRefusedSend(t *testing.T) {
+	pl, _ := newTestPool(t, 1, WithStrictValidation())
+	err := pl.Send(context.Background(), "^FDno format^FS")
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Send = %v, want a *ValidationError", err)
+	}
+	if n := len(pl.idle); n != 1 {
+		t.Errorf("%d idle connections after a send that wrote nothing, want 1", n)
+	}
+}
+
+// Regression test: a send cut off part way used to put the connection
+// back in the pool, so the next label was appended to the partial one.
+func TestNetworkPrinterPoolDiscardsPartialSend(t *testing.T) {
+	// A printer that takes the first byte, then stops reading
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		c.Read(make([]byte, 1))
+		cancel()
+		<-stop
+	}()
+	pl, err := NewNetworkPrinterPool(ln.Addr().String(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pl.Close()
+
+	big := "^XA^FD" + strings.Repeat("x", 16<<20) + "^FS^XZ"
+	if err := pl.Send(ctx, big); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Send = %v, want context.Canceled", err)
+	}
+	if n := len(pl.idle); n != 0 {
+		t.Errorf("%d idle connections after a partial send, want 0", n)
+	}
+	if n := len(pl.slots); n != 0 {
+		t.Errorf("%d slots still taken after the connection was discarded", n)
+	}
+}
+
+func TestNetworkPrinterPoolClose(t *testing.T) {
+	pl, _ := newTestPool(t, 2)
+	ctx := context.Background()
+	idle, err := pl.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy, err := pl.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl.Release(idle)
+	if err := pl.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := idle.Ping(); err == nil {
+		t.Error("idle connection still open after Close")
+	}
+	if _, err := pl.Acquire(ctx); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Acquire after Close = %v, want ErrPoolClosed", err)
+	}
+	// A connection in use is closed when it comes back
+	pl.Release(busy)
+	if err := busy.Ping(); err == nil {
+		t.Error("connection released after Close is still open")
+	}
+	if err := pl.Send(ctx, "^XA^XZ"); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Send after Close = %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestNewNetworkPrinterPoolInvalidSize(t *testing.T) {
+	if _, err := NewNetworkPrinterPool("127.0.0.1:9100", 0); err == nil {
+		t.Error("pool of size 0 created")
+	}
+}
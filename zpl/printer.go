@@ -0,0 +1,91 @@
+// Package zpl sends ZPL label formats to Zebra printers over USB, TCP or a
+// serial port.
+package zpl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// PrinterConnection is an open connection to a Zebra printer.
+type PrinterConnection interface {
+	// SendZPL writes a ZPL payload to the printer, appending a trailing
+	// newline if it has none.
+	SendZPL(zpl string) error
+	// SendZPLN is like SendZPL but also returns the number of bytes
+	// written, including the added newline, so a short write can be told
+	// apart from success.
+	SendZPLN(zpl string) (int, error)
+	// SendZPLContext is like SendZPL but gives up when ctx is done,
+	// returning ctx.Err().
+	SendZPLContext(ctx context.Context, zpl string) error
+	// SendZPLReader streams r to the printer without loading it into
+	// memory. The trailing newline is added after the last byte if needed;
+	// strict validation does not apply to streams.
+	SendZPLReader(r io.Reader) error
+	// SendRaw writes data exactly as given and returns how many bytes were
+	// written: no newline is added, no encoding is applied and strict
+	// validation does not apply. Use it for downloads whose byte count must
+	// match, such as ~DY, ~DG or ^GF with binary data.
+	SendRaw(data []byte) (int, error)
+	// RawSend is SendRaw without the byte count.
+	RawSend(data []byte) error
+	// SetDeadline sets both the write deadline and, on connections that
+	// read responses, the read deadline, see SetWriteDeadline. Queries such
+	// as Status and GetVar keep their own timeout.
+	SetDeadline(t time.Time) error
+	// SetWriteDeadline bounds the sends that start afterwards: a send still
+	// running at t is aborted and fails with context.DeadlineExceeded. A
+	// zero t clears the deadline.
+	SetWriteDeadline(t time.Time) error
+	// Ping checks that the printer is reachable; nil means healthy.
+	Ping() error
+	// Close releases the underlying device or socket.
+	Close() error
+}
+
+// withNewline terminates zpl with a newline, as the printer expects.
+func withNewline(zpl string) string {
+	if !strings.HasSuffix(zpl, "\n") {
+		return zpl + "\n"
+	}
+	return zpl
+}
+
+// streamZPL copies r to write in chunks of chunkSize bytes, then terminates
+// the stream with a newline if its last byte was not one. It returns the
+// number of bytes written. Errors from write are returned as they are.
+func streamZPL(write func([]byte) (int, error), r io.Reader, chunkSize int) (int64, error) {
+	buf := make([]byte, chunkSize)
+	var (
+		last byte
+		sent int64
+	)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			w, werr := write(buf[:n])
+			sent += int64(w)
+			if werr != nil {
+				return sent, werr
+			}
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return sent, fmt.Errorf("failed to read ZPL: %w", err)
+		}
+	}
+	if last != '\n' {
+		if _, err := write([]byte("\n")); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,45 @@
+package zpl
+
+import "fmt"
+
+// PrintMode is a ^MM media handling mode: what the printer does with each
+// label once it is printed.
+type PrintMode byte
+
+// Print modes accepted by LabelBuilder.PrintMode. Not every printer has
+// the hardware for every mode; a missing cutter or peeler is reported by
+// the printer, not the builder.
+const (
+	PrintModeTearOff    PrintMode = 'T' // stop at the tear bar
+	PrintModePeelOff    PrintMode = 'P' // peel the label off its liner
+	PrintModeRewind     PrintMode = 'R' // rewind labels on their liner
+	PrintModeApplicator PrintMode = 'A' // hand each label to an applicator
+	PrintModeCutter     PrintMode = 'C' // cut after every label
+	PrintModeDelayedCut PrintMode = 'D' // cut when the ~JK command arrives
+	PrintModeRFID       PrintMode = 'F' // RFID encoding
+	PrintModeKiosk      PrintMode = 'K' // kiosk presenter
+)
+
+// PrintMode sets the media handling mode with ^MM, such as
+// PrintModeCutter for an auto-cut workflow. The printer keeps the mode for
+// the following formats too.
+func (b *LabelBuilder) PrintMode(mode PrintMode) *LabelBuilder {
+	switch mode {
+	case PrintModeTearOff, PrintModePeelOff, PrintModeRewind, PrintModeApplicator,
+		PrintModeCutter, PrintModeDelayedCut, PrintModeRFID, PrintModeKiosk:
+	default:
+		b.fail(fmt.Errorf("invalid print mode %q: must be T, P, R, A, C, D, F or K", rune(mode)))
+		return b
+	}
+	return b.setup("^MM", fmt.Sprintf("^MM%c", mode))
+}
+
+// MapClear sets with ^MC whether the label image is cleared after the
+// format prints. With clear false the next format prints over this one's
+// image, so only the fields that change need to be sent.
+func (b *LabelBuilder) MapClear(clear bool) *LabelBuilder {
+	if clear {
+		return b.setup("^MC", "^MCY")
+	}
+	return b.setup("^MC", "^MCN")
+}
@@ -0,0 +1,79 @@
+package zpl
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// queueRetryDelay is the pause between attempts at a failed job.
+const queueRetryDelay = 500 * time.Millisecond
+
+// ErrQueueClosed is returned by Enqueue after the queue has been closed.
+var ErrQueueClosed = errors.New("print queue closed")
+
+// PrintQueue feeds labels to a printer from a background goroutine, so
+// producers are not held up by the printer's speed.
+type PrintQueue struct {
+	printer PrinterConnection
+	retries int
+	onError func(zpl string, err error)
+
+	mu     sync.RWMutex
+	closed bool
+	jobs   chan string
+	done   chan struct{}
+}
+
+// NewPrintQueue starts a queue holding up to size pending jobs for p. A job
+// that still fails after retries further attempts is passed to onError,
+// which may be nil.
+func NewPrintQueue(p PrinterConnection, size, retries int, onError func(zpl string, err error)) *PrintQueue {
+	q := &PrintQueue{
+		printer: p,
+		retries: retries,
+		onError: onError,
+		jobs:    make(chan string, size),
+		done:    make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Enqueue adds a job to the queue, blocking while the queue is full.
+func (q *PrintQueue) Enqueue(zpl string) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	q.jobs <- zpl
+	return nil
+}
+
+// Close stops accepting jobs and waits until every queued job has been
+// sent or reported to the error callback. The printer itself is left open.
+func (q *PrintQueue) Close() error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+	<-q.done
+	return nil
+}
+
+func (q *PrintQueue) run() {
+	defer close(q.done)
+	for zpl := range q.jobs {
+		err := q.printer.SendZPL(zpl)
+		for attempt := 0; err != nil && attempt < q.retries; attempt++ {
+			time.Sleep(queueRetryDelay)
+			err = q.printer.SendZPL(zpl)
+		}
+		if err != nil && q.onError != nil {
+			q.onError(zpl, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestPrintQueueOrder(t *testing.T) {
+	m := NewMockPrinter()
+	q := NewPrintQueue(m, 4, 0, nil)
+	var want []string
+	for i := range 50 {
+		job := fmt.Sprintf("^XA^FD%d^FS^XZ", i)
+		want = append(want, job)
+		if err := q.Enqueue(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Sent(); !slices.Equal(got, want) {
+		t.Errorf("sent %q, want %q", got, want)
+	}
+}
+
+func TestPrintQueueConcurrentProducers(t *testing.T) {
+	m := NewMockPrinter()
+	q := NewPrintQueue(m, 2, 0, nil)
+	const producers, jobs = 4, 25
+	var wg sync.WaitGroup
+	for p := range producers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := q.Enqueue(fmt.Sprintf("%d:%02d", p, i)); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	q.Close()
+
+	// Each producer's jobs are sent in the order it queued them
+	sent := m.Sent()
+	if len(sent) != producers*jobs {
+		t.Fatalf("sent %d jobs, want %d", len(sent), producers*jobs)
+	}
+	for p := range producers {
+		var mine []string
+		for _, s := range sent {
+			if s[0] == byte('0'+p) {
+				mine = append(mine, s)
+			}
+		}
+		if !slices.IsSorted(mine) || len(mine) != jobs {
+			t.Errorf("producer %d jobs sent as %q", p, mine)
+		}
+	}
+}
+
+func TestPrintQueueClose(t *testing.T) {
+	m := NewMockPrinter()
+	q := NewPrintQueue(m, 10, 0, nil)
+	for range 10 {
+		q.Enqueue("^XA^XZ")
+	}
+	// Close waits for the backlog to drain
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(m.Sent()); n != 10 {
+		t.Errorf("%d jobs sent before Close returned, want 10", n)
+	}
+	if err := q.Enqueue("^XA^XZ"); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Enqueue after Close = %v, want ErrQueueClosed", err)
+	}
+	if err := q.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+}
+
+func TestPrintQueueErrors(t *testing.T) {
+	errPaper := errors.New("paper out")
+	tests := []struct {
+		name    string
+		retries int
+		sent    []string
+		failed  []string
+	}{
+		{"no retries", 0, []string{"a", "c"}, []string{"b"}},
+		{"retried", 1, []string{"a", "b", "c"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockPrinter()
+			m.FailOn(2, errPaper)
+			var failed []string
+			q := NewPrintQueue(m, 3, tt.retries, func(zpl string, err error) {
+				if !errors.Is(err, errPaper) {
+					t.Errorf("onError(%q, %v), want the send error", zpl, err)
+				}
+				failed = append(failed, zpl)
+			})
+			for _, job := range []string{"a", "b", "c"} {
+				q.Enqueue(job)
+			}
+			q.Close()
+			if got := m.Sent(); !slices.Equal(got, tt.sent) {
+				t.Errorf("sent %q, want %q", got, tt.sent)
+			}
+			if !slices.Equal(failed, tt.failed) {
+				t.Errorf("onError got %q, want %q", failed, tt.failed)
+			}
+		})
+	}
+}
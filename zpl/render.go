@@ -0,0 +1,423 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Default label size in inches when the format has no ^PW or ^LL.
+const (
+	defaultLabelWidthIn  = 4
+	defaultLabelLengthIn = 6
+)
+
+// maxLabelDots is the largest label width and length ^PW and ^LL accept.
+const maxLabelDots = 32000
+
+// maxRenderPixels bounds the preview image, one byte per pixel, so that
+// ZPL from an untrusted source cannot make Render allocate gigabytes. It
+// fits an 8 by 12 inch label at 600 dpi.
+const maxRenderPixels = 64 << 20
+
+// qrCapacity is the byte-mode capacity of QR versions 1-40 at error
+// correction level Q.
+var qrCapacity = []int{
+	11, 20, 32, 46, 60, 74, 86, 108, 130, 151,
+	177, 203, 241, 258, 292, 322, 364, 394, 442, 482,
+	509, 565, 611, 661, 715, 751, 805, 868, 908, 982,
+	1030, 1112, 1168, 1228, 1283, 1351, 1423, 1499, 1579, 1663,
+}
+
+// dataMatrixSizes lists square ECC 200 symbol sizes with their approximate
+// ASCII capacity.
+var dataMatrixSizes = []struct{ modules, capacity int }{
+	{10, 3}, {12, 5}, {14, 8}, {16, 12}, {18, 18}, {20, 22}, {22, 30},
+	{24, 36}, {26, 44}, {32, 62}, {36, 86}, {40, 114}, {44, 144},
+	{48, 174}, {52, 204}, {64, 280}, {72, 368}, {80, 456}, {88, 576},
+	{96, 696}, {104, 816}, {120, 1050}, {132, 1304}, {144, 1558},
+}
+
+// Render draws an approximate preview of the first label format in zpl at
+// dpi dots per inch. See RenderWithWarnings.
+func Render(zpl string, dpi int) (image.Image, error) {
+	img, _, err := RenderWithWarnings(zpl, dpi)
+	return img, err
+}
+
+// RenderWithWarnings draws an approximate preview of the first label format
+// in zpl and also returns a warning for every unsupported command and every
+// element that falls outside the label.
+//
+// It understands ^FO, ^FT, ^LH, ^PW, ^LL, ^CF, ^A text, ^GB boxes and the
+// ^BC, ^B3, ^BQ and ^BX barcodes, with ^BY for module width. Text uses a
+// scaled bitmap font and barcodes are drawn at their real size with
+// placeholder bars, so the preview shows layout, overlaps and clipping but
+// its barcodes will not scan. Labels of more than 64 megapixels, such as
+// ^PW32000^LL32000, are refused with an error rather than allocated.
+func RenderWithWarnings(zpl string, dpi int) (image.Image, []string, error) {
+	if dpi <= 0 {
+		return nil, nil, fmt.Errorf("invalid resolution %d dpi", dpi)
+	}
+	cmds := renderCommands(zpl)
+	if len(cmds) == 0 {
+		return nil, nil, errors.New("no ZPL commands to render")
+	}
+
+	r := &renderer{
+		width:  min(defaultLabelWidthIn*dpi, maxLabelDots),
+		length: min(defaultLabelLengthIn*dpi, maxLabelDots),
+		font:   "0",
+		fontH:  9,
+		fontW:  5,
+		module: 2,
+		warned: map[string]bool{},
+	}
+	// ^PW and ^LL may come anywhere in the format but size the whole label
+	for _, c := range cmds {
+		switch c.code {
+		case "PW":
+			r.width = r.labelSize(c, r.width)
+		case "LL":
+			r.length = r.labelSize(c, r.length)
+		}
+	}
+	if r.width*r.length > maxRenderPixels {
+		return nil, nil, fmt.Errorf("label of %dx%d dots is too large to render: the limit is %d pixels", r.width, r.length, maxRenderPixels)
+	}
+	r.img = image.NewGray(image.Rect(0, 0, r.width, r.length))
+	draw.Draw(r.img, r.img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for _, c := range cmds {
+		r.exec(c)
+		if c.code == "XZ" {
+			break
+		}
+	}
+	return r.img, r.warnings, nil
+}
+
+type renderCmd struct {
+	code   string
+	params string
+	offset int
+}
+
+// renderCommands splits zpl into ^ format commands with ParseZPL; tilde
+// control commands have no visual effect and are skipped. Field data keeps
+// its whitespace, other parameters are trimmed.
+func renderCommands(zpl string) []renderCmd {
+	var cmds []renderCmd
+	for _, c := range ParseZPL(zpl) {
+		if c.Prefix == '~' {
+			continue
+		}
+		params := c.String()[1+len(c.Code):]
+		if c.Code != "FD" && c.Code != "FV" {
+			params = strings.TrimSpace(params)
+		}
+		cmds = append(cmds, renderCmd{c.Code, params, c.Offset})
+	}
+	return cmds
+}
+
+type renderer struct {
+	img           *image.Gray
+	width, length int
+	warnings      []string
+	warned        map[string]bool
+
+	homeX, homeY int
+	x, y         int
+	baseline     bool // origin set by ^FT
+
+	font         string
+	fontH, fontW int
+	fieldFont    bool // ^A seen for the current field
+	fieldH       int
+	fieldW       int
+
+	module  int
+	barcode string // pending barcode command
+	bcArgs  []string
+}
+
+func (r *renderer) exec(c renderCmd) {
+	switch c.code {
+	case "XA", "XZ", "FX", "CI", "PQ", "PW", "LL", "MU", "PR", "MD", "PO", "MM", "MC", "JJ", "CC", "CT":
+		// No visual effect in the preview
+	case "LH":
+		r.homeX = atoiOr(param(c.params, 0), 0)
+		r.homeY = atoiOr(param(c.params, 1), 0)
+	case "FO", "FT":
+		r.x = r.homeX + atoiOr(param(c.params, 0), 0)
+		r.y = r.homeY + atoiOr(param(c.params, 1), 0)
+		r.baseline = c.code == "FT"
+	case "CF":
+		if f := param(c.params, 0); f != "" {
+			r.font = f
+		}
+		r.fontH = atoiOr(param(c.params, 1), r.fontH)
+		if r.fontW = atoiOr(param(c.params, 2), r.fontH); r.fontW <= 0 {
+			// A zero width scales with the height
+			r.fontW = r.fontH
+		}
+	case "A":
+		// ^Afo,h,w: font letter and orientation are glued together
+		if len(c.params) > 0 && c.params[0] != '@' {
+			r.font = c.params[:1]
+		}
+		r.fieldFont = true
+		r.fieldH = atoiOr(param(c.params, 1), r.fontH)
+		r.fieldW = atoiOr(param(c.params, 2), r.fieldH)
+		if fo := param(c.params, 0); len(fo) > 1 && fo[1] != 'N' {
+			r.warn("rotated text", c.offset)
+		}
+	case "BY":
+		r.module = atoiOr(param(c.params, 0), r.module)
+	case "BC", "B3", "BQ", "BX":
+		r.barcode = c.code
+		r.bcArgs = strings.Split(c.params, ",")
+	case "GB":
+		r.box(c)
+	case "FD", "FV":
+		r.field(c)
+	case "FS":
+		r.endField()
+	default:
+		r.warn("^"+c.code, c.offset)
+	}
+}
+
+// field draws the data of the current field as text or as the pending
+// barcode.
+func (r *renderer) field(c renderCmd) {
+	switch r.barcode {
+	case "BC":
+		r.linear(c, 11*(len(c.params)+2)+13)
+	case "B3":
+		r.linear(c, 16*(len(c.params)+2))
+	case "BQ":
+		r.qr(c)
+	case "BX":
+		r.dataMatrix(c)
+	default:
+		h, w := r.fontH, r.fontW
+		if r.fieldFont {
+			h, w = r.fieldH, r.fieldW
+		}
+		x, y := r.x, r.y
+		if r.baseline {
+			y -= h
+		}
+		r.text(x, y, h, w, c.params, c.offset)
+	}
+}
+
+func (r *renderer) endField() {
+	r.barcode = ""
+	r.bcArgs = nil
+	r.fieldFont = false
+	r.baseline = false
+}
+
+// linear draws a 1D barcode of the given width in modules, with its
+// interpretation line when requested.
+func (r *renderer) linear(c renderCmd, modules int) {
+	h := atoiOr(arg(r.bcArgs, 1), defaultBarHeight)
+	line := arg(r.bcArgs, 2) != "N"
+	if r.barcode == "B3" {
+		h = atoiOr(arg(r.bcArgs, 2), defaultBarHeight)
+		line = arg(r.bcArgs, 3) != "N"
+	}
+	if o := arg(r.bcArgs, 0); o != "" && o != "N" {
+		r.warn("rotated barcode", c.offset)
+	}
+	x, y := r.x, r.y
+	if r.baseline {
+		y -= h
+	}
+	w := modules * r.module
+	r.check(image.Rect(x, y, x+w, y+h), c.offset)
+	// Placeholder bars: one module on, one off
+	for m := 0; m < modules; m += 2 {
+		r.fill(image.Rect(x+m*r.module, y, x+(m+1)*r.module, y+h))
+	}
+	if line {
+		th := 9 * r.module
+		r.text(x, y+h+r.module, th, th*6/10+1, c.params, c.offset)
+	}
+}
+
+func (r *renderer) qr(c renderCmd) {
+	mag := atoiOr(arg(r.bcArgs, 2), 1)
+	data := c.params
+	if i := strings.Index(data, ","); i >= 0 {
+		data = data[i+1:]
+	}
+	version := len(qrCapacity)
+	for v, capacity := range qrCapacity {
+		if len(data) <= capacity {
+			version = v + 1
+			break
+		}
+	}
+	// ^BQ leaves a margin of roughly ten dots above the symbol
+	r.matrix(r.x, r.y+10, 17+4*version, mag, true, c.offset)
+}
+
+func (r *renderer) dataMatrix(c renderCmd) {
+	size := dataMatrixSizes[len(dataMatrixSizes)-1].modules
+	for _, s := range dataMatrixSizes {
+		if len(c.params) <= s.capacity {
+			size = s.modules
+			break
+		}
+	}
+	r.matrix(r.x, r.y, size, atoiOr(arg(r.bcArgs, 1), defaultModuleSize), false, c.offset)
+}
+
+// matrix draws a placeholder 2D symbol of modules x modules cells, with QR
+// finder patterns or the Data Matrix L-shaped finder.
+func (r *renderer) matrix(x, y, modules, cell int, qr bool, offset int) {
+	size := modules * cell
+	r.check(image.Rect(x, y, x+size, y+size), offset)
+	for my := 0; my < modules; my++ {
+		for mx := 0; mx < modules; mx++ {
+			on := (mx+my)%2 == 0
+			if qr {
+				on = on && mx%3 != 0
+			} else {
+				on = mx == 0 || my == modules-1 ||
+					(my == 0 && mx%2 == 0) || (mx == modules-1 && my%2 == 1) || on
+			}
+			if on {
+				r.fill(image.Rect(x+mx*cell, y+my*cell, x+(mx+1)*cell, y+(my+1)*cell))
+			}
+		}
+	}
+	if qr {
+		for _, p := range []image.Point{{0, 0}, {modules - 7, 0}, {0, modules - 7}} {
+			fx, fy := x+p.X*cell, y+p.Y*cell
+			r.clear(image.Rect(fx, fy, fx+7*cell, fy+7*cell))
+			r.frame(image.Rect(fx, fy, fx+7*cell, fy+7*cell), cell)
+			r.fill(image.Rect(fx+2*cell, fy+2*cell, fx+5*cell, fy+5*cell))
+		}
+	}
+}
+
+// box draws a ^GBw,h,t,c graphic box.
+func (r *renderer) box(c renderCmd) {
+	t := atoiOr(param(c.params, 2), 1)
+	w := max(atoiOr(param(c.params, 0), t), t)
+	h := max(atoiOr(param(c.params, 1), t), t)
+	rect := image.Rect(r.x, r.y, r.x+w, r.y+h)
+	r.check(rect, c.offset)
+	if param(c.params, 3) == "W" {
+		r.warn("white ^GB boxes", c.offset)
+		return
+	}
+	r.frame(rect, t)
+}
+
+// text draws s with character cells of h by w dots. The scalable font 0 is
+// roughly 60% as wide as its nominal width.
+func (r *renderer) text(x, y, h, w int, s string, offset int) {
+	if s == "" || h <= 0 || w <= 0 {
+		return
+	}
+	if r.font == "0" {
+		w = max(w*6/10, 1)
+	}
+	r.check(image.Rect(x, y, x+w*len(s), y+h), offset)
+
+	// Draw with the 7x13 bitmap face, then scale each pixel to the cell
+	face := basicfont.Face7x13
+	src := image.NewAlpha(image.Rect(0, 0, 7*len(s), 13))
+	d := font.Drawer{Dst: src, Src: image.Opaque, Face: face, Dot: fixed.P(0, face.Ascent)}
+	d.DrawString(s)
+	for sy := 0; sy < 13; sy++ {
+		for sx := 0; sx < 7*len(s); sx++ {
+			if src.AlphaAt(sx, sy).A < 0x80 {
+				continue
+			}
+			r.fill(image.Rect(x+sx*w/7, y+sy*h/13, x+(sx+1)*w/7, y+(sy+1)*h/13))
+		}
+	}
+}
+
+func (r *renderer) fill(rect image.Rectangle) {
+	draw.Draw(r.img, rect, image.Black, image.Point{}, draw.Src)
+}
+
+func (r *renderer) clear(rect image.Rectangle) {
+	draw.Draw(r.img, rect, image.White, image.Point{}, draw.Src)
+}
+
+// frame draws the border of rect t dots thick, filling it when t covers it.
+func (r *renderer) frame(rect image.Rectangle, t int) {
+	if 2*t >= rect.Dx() || 2*t >= rect.Dy() {
+		r.fill(rect)
+		return
+	}
+	r.fill(image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y+t))
+	r.fill(image.Rect(rect.Min.X, rect.Max.Y-t, rect.Max.X, rect.Max.Y))
+	r.fill(image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+t, rect.Max.Y))
+	r.fill(image.Rect(rect.Max.X-t, rect.Min.Y, rect.Max.X, rect.Max.Y))
+}
+
+// labelSize returns the size set by the ^PW or ^LL command c, or def if it
+// has none. A size outside what the printer accepts is clamped, with a
+// warning, so untrusted input cannot make the preview arbitrarily large.
+func (r *renderer) labelSize(c renderCmd, def int) int {
+	n := atoiOr(param(c.params, 0), def)
+	if n < 1 || n > maxLabelDots {
+		r.warnings = append(r.warnings, fmt.Sprintf("offset %d: ^%s%d outside 1 to %d dots, clamped", c.offset, c.code, n, maxLabelDots))
+		n = min(max(n, 1), maxLabelDots)
+	}
+	return n
+}
+
+// check warns when rect does not fit on the label.
+func (r *renderer) check(rect image.Rectangle, offset int) {
+	if !rect.In(r.img.Bounds()) {
+		r.warnings = append(r.warnings, fmt.Sprintf("offset %d: element at %d,%d size %dx%d extends beyond the %dx%d label",
+			offset, rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy(), r.width, r.length))
+	}
+}
+
+// warn records an unsupported feature once.
+func (r *renderer) warn(what string, offset int) {
+	if r.warned[what] {
+		return
+	}
+	r.warned[what] = true
+	r.warnings = append(r.warnings, fmt.Sprintf("offset %d: %s not supported, ignored", offset, what))
+}
+
+// param returns the i-th comma-separated parameter, trimmed.
+func param(params string, i int) string {
+	return arg(strings.Split(params, ","), i)
+}
+
+func arg(args []string, i int) string {
+	if i >= len(args) {
+		return ""
+	}
+	return strings.TrimSpace(args[i])
+}
+
+func atoiOr(s string, def int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return def
+	}
+	return n
+}
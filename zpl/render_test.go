@@ -0,0 +1,56 @@
+package zpl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderLabelSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		zpl           string
+		width, length int
+		warn          bool
+	}{
+		{"default", "^XA^XZ", 812, 1218, false},
+		{"set", "^XA^PW400^LL300^XZ", 400, 300, false},
+		{"too wide", "^XA^PW999999^LL100^XZ", maxLabelDots, 100, true},
+		{"too long", "^XA^PW100^LL999999^XZ", 100, maxLabelDots, true},
+		{"zero", "^XA^PW0^LL100^XZ", 1, 100, true},
+		{"negative", "^XA^PW100^LL-5^XZ", 100, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, warnings, err := RenderWithWarnings(tt.zpl, 203)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.width || b.Dy() != tt.length {
+				t.Errorf("rendered %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.width, tt.length)
+			}
+			clamped := strings.Contains(strings.Join(warnings, "\n"), "clamped")
+			if clamped != tt.warn {
+				t.Errorf("warnings %q, want a clamping warning %v", warnings, tt.warn)
+			}
+		})
+	}
+}
+
+func TestRenderTooLarge(t *testing.T) {
+	tests := []struct {
+		name string
+		zpl  string
+		dpi  int
+	}{
+		{"largest label", "^XA^PW32000^LL32000^XZ", 203},
+		{"clamped sizes", "^XA^PW999999^LL999999^XZ", 203},
+		{"default size at a huge resolution", "^XA^XZ", 100000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Render(tt.zpl, tt.dpi); err == nil {
+				t.Error("no error for a label over the pixel limit")
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package zpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// responseTimeout bounds how long a query waits for the printer's answer.
+const responseTimeout = 5 * time.Second
+
+// Framing bytes around each ~HS and ~HI response string.
+const (
+	stx = "\x02"
+	etx = "\x03"
+)
+
+// responder is implemented by printers that can read replies back from the
+// device.
+type responder interface {
+	PrinterConnection
+	// acquire takes exclusive use of the connection until the returned
+	// function is called.
+	acquire() (release func())
+	// send is SendZPLContext for a caller that has acquired the connection.
+	// It returns the number of bytes written.
+	send(ctx context.Context, zpl string) (int, error)
+	// readDeadline reads into b, failing once deadline has passed.
+	readDeadline(b []byte, deadline time.Time) (int, error)
+	// closed returns a channel that is closed once the printer is.
+	closed() <-chan struct{}
+	// alertReader returns the background reader of AlertChannel, starting
+	// it if needed, for a caller that has acquired the connection.
+	alertReader() (*alertReader, error)
+}
+
+// query sends cmd to p and collects the reply until complete reports that
+// it has fully arrived or timeout expires. Other sends wait until the
+// exchange is over, so replies are not interleaved.
+func query(p PrinterConnection, cmd string, complete func(resp string) bool, timeout time.Duration) (string, error) {
+	r, ok := asResponder(p)
+	if !ok {
+		return "", ErrNotBidirectional
+	}
+	release := r.acquire()
+	defer release()
+	if _, err := r.send(context.Background(), cmd); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(timeout)
+	var resp []byte
+	buf := make([]byte, 512)
+	for !complete(string(resp)) {
+		n, err := r.readDeadline(buf, deadline)
+		resp = append(resp, buf[:n]...)
+		if err != nil {
+			return string(resp), fmt.Errorf("failed to read response: %w", err)
+		}
+	}
+	return string(resp), nil
+}
+
+// Query sends command to p and reads the reply up to and including the
+// first terminator byte, such as ETX (0x03) or '\r', giving up after
+// timeout; zero or less uses the default of 5 seconds. Anything the printer
+// sent after the terminator is discarded. It is the primitive
+// behind Status and GetVar, for host commands and SGD queries the package
+// does not wrap. Sends from other goroutines wait until the exchange is
+// over. p must be able to read responses back, or the error is
+// ErrNotBidirectional.
+func Query(p PrinterConnection, command string, terminator byte, timeout time.Duration) (string, error) {
+	if timeout <= 0 {
+		timeout = responseTimeout
+	}
+	resp, err := query(p, command, func(resp string) bool {
+		return strings.IndexByte(resp, terminator) >= 0
+	}, timeout)
+	if i := strings.IndexByte(resp, terminator); i >= 0 {
+		resp = resp[:i+1]
+	}
+	return resp, err
+}
+
+// wrapper is implemented by decorators around a PrinterConnection.
+type wrapper interface {
+	unwrap() PrinterConnection
+}
+
+// asResponder finds the printer that can read replies beneath any
+// decorators around p.
+func asResponder(p PrinterConnection) (responder, bool) {
+	for {
+		if r, ok := p.(responder); ok {
+			return r, true
+		}
+		w, ok := p.(wrapper)
+		if !ok {
+			return nil, false
+		}
+		p = w.unwrap()
+	}
+}
+
+// frames completes a query once n ETX-terminated strings have arrived.
+// ~HS answers with three, ~HI with one.
+func frames(n int) func(string) bool {
+	return func(resp string) bool {
+		return strings.Count(resp, etx) >= n
+	}
+}
+
+// quoted completes a query once a double-quoted SGD value has arrived.
+func quoted(resp string) bool {
+	return strings.Count(resp, `"`) >= 2
+}
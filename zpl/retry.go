@@ -0,0 +1,94 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// retryPrinter is the decorator returned by WithRetry.
+type retryPrinter struct {
+	PrinterConnection
+	attempts int
+	delay    time.Duration
+}
+
+// WithRetry wraps p so that SendZPL, SendZPLN, SendZPLContext, SendRaw
+// and RawSend are tried up to attempts times in total, waiting delay
+// between tries. Only transient failures are retried: those wrapping
+// ErrNotConnected and network timeouts. Others, such as a *ValidationError
+// from WithStrictValidation, would fail the same way again and are
+// returned at once.
+// Streams cannot be replayed, so SendZPLReader is not retried. Ping, Close
+// and queries such as GetVar go straight to p.
+//
+// A send that fails partway through is tried again from the start, so the
+// printer may get the front of the payload twice: a format that reached it
+// whole before the failure prints again. Retry payloads of one format, or
+// ones that are safe to repeat.
+func WithRetry(p PrinterConnection, attempts int, delay time.Duration) PrinterConnection {
+	return &retryPrinter{PrinterConnection: p, attempts: max(attempts, 1), delay: delay}
+}
+
+func (r *retryPrinter) SendZPL(zpl string) error {
+	return r.SendZPLContext(context.Background(), zpl)
+}
+
+func (r *retryPrinter) SendZPLContext(ctx context.Context, zpl string) error {
+	return r.retry(ctx, func() error {
+		return r.PrinterConnection.SendZPLContext(ctx, zpl)
+	})
+}
+
+func (r *retryPrinter) SendZPLN(zpl string) (int, error) {
+	var n int
+	err := r.retry(context.Background(), func() error {
+		var err error
+		n, err = r.PrinterConnection.SendZPLN(zpl)
+		return err
+	})
+	return n, err
+}
+
+func (r *retryPrinter) SendRaw(data []byte) (int, error) {
+	var n int
+	err := r.retry(context.Background(), func() error {
+		var err error
+		n, err = r.PrinterConnection.SendRaw(data)
+		return err
+	})
+	return n, err
+}
+
+func (r *retryPrinter) RawSend(data []byte) error {
+	return r.retry(context.Background(), func() error {
+		return r.PrinterConnection.RawSend(data)
+	})
+}
+
+func (r *retryPrinter) unwrap() PrinterConnection {
+	return r.PrinterConnection
+}
+
+// retry calls send until it succeeds, fails for good, the attempts run out
+// or ctx is done, and returns the last error.
+func (r *retryPrinter) retry(ctx context.Context, send func() error) error {
+	var err error
+	for attempt := 1; attempt <= r.attempts; attempt++ {
+		if err = send(); !isTransient(err) || attempt == r.attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.delay):
+		}
+	}
+	return err
+}
+
+// isTransient reports whether a send that failed with err may succeed when
+// tried again.
+func isTransient(err error) bool {
+	return errors.Is(err, ErrNotConnected) || isTimeout(err)
+}
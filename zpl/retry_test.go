@@ -0,0 +1,105 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// flakyPrinter fails its first fails sends with err before passing them to
+// the MockPrinter.
+type flakyPrinter struct {
+	*MockPrinter
+	fails int
+	err   error
+	tries int
+}
+
+func (f *flakyPrinter) fail() error {
+	if f.tries++; f.tries <= f.fails {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyPrinter) SendZPLContext(ctx context.Context, zpl string) error {
+	if err := f.fail(); err != nil {
+		return err
+	}
+	return f.MockPrinter.SendZPLContext(ctx, zpl)
+}
+
+func (f *flakyPrinter) SendZPLN(zpl string) (int, error) {
+	if err := f.fail(); err != nil {
+		return 0, err
+	}
+	return f.MockPrinter.SendZPLN(zpl)
+}
+
+func (f *flakyPrinter) SendRaw(data []byte) (int, error) {
+	if err := f.fail(); err != nil {
+		return 0, err
+	}
+	return f.MockPrinter.SendRaw(data)
+}
+
+func TestWithRetry(t *testing.T) {
+	lost := fmt.Errorf("%w: connection reset", ErrNotConnected)
+	tests := []struct {
+		name   string
+		fails  int
+		err    error
+		tries  int
+		wantOK bool
+	}{
+		{"first try", 0, lost, 1, true},
+		{"fails twice", 2, lost, 3, true},
+		{"runs out of attempts", 3, lost, 3, false},
+		{"timeout", 1, context.DeadlineExceeded, 2, true},
+		{"permanent failure", 1, &ValidationError{Reason: "bad"}, 1, false},
+	}
+	sends := []struct {
+		name string
+		send func(PrinterConnection) error
+	}{
+		{"SendZPL", func(p PrinterConnection) error { return p.SendZPL("^XA^XZ") }},
+		{"SendZPLN", func(p PrinterConnection) error { _, err := p.SendZPLN("^XA^XZ"); return err }},
+		{"SendRaw", func(p PrinterConnection) error { _, err := p.SendRaw([]byte("~JA")); return err }},
+	}
+	for _, tt := range tests {
+		for _, s := range sends {
+			t.Run(tt.name+"/"+s.name, func(t *testing.T) {
+				f := &flakyPrinter{MockPrinter: NewMockPrinter(), fails: tt.fails, err: tt.err}
+				err := s.send(WithRetry(f, 3, time.Millisecond))
+				if f.tries != tt.tries {
+					t.Errorf("tried %d times, want %d", f.tries, tt.tries)
+				}
+				if tt.wantOK {
+					if err != nil {
+						t.Fatal(err)
+					}
+					if n := len(f.Sent()); n != 1 {
+						t.Errorf("payload recorded %d times, want once", n)
+					}
+				} else if !errors.Is(err, tt.err) {
+					t.Errorf("err = %v, want %v", err, tt.err)
+				}
+			})
+		}
+	}
+}
+
+func TestWithRetryContextDone(t *testing.T) {
+	f := &flakyPrinter{MockPrinter: NewMockPrinter(), fails: 5, err: ErrNotConnected}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := WithRetry(f, 5, time.Hour).SendZPLContext(ctx, "^XA^XZ")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if f.tries != 1 {
+		t.Errorf("tried %d times after the context was done, want 1", f.tries)
+	}
+}
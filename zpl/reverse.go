@@ -0,0 +1,25 @@
+package zpl
+
+// Reverse prints the next field in reverse with ^FR: its dots are
+// inverted against whatever is already printed beneath, so text drawn over
+// a solid Box comes out white on black. ^FR is written right after the
+// field's ^FO, ahead of its font, barcode and ^FD. Called while a field
+// started with Field is still open, it applies to that field instead.
+func (b *LabelBuilder) Reverse() *LabelBuilder {
+	if b.fieldOpen {
+		b.body.WriteString("^FR")
+		return b
+	}
+	b.reverse = true
+	return b
+}
+
+// ReverseAll sets with ^LR whether every field of the format is printed in
+// reverse, as Reverse does for one field. The printer keeps the setting
+// for the following formats too.
+func (b *LabelBuilder) ReverseAll(on bool) *LabelBuilder {
+	if on {
+		return b.setup("^LR", "^LRY")
+	}
+	return b.setup("^LR", "^LRN")
+}
@@ -0,0 +1,99 @@
+package zpl
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// serialChunkSize is how much is written between cancellation checks.
+const serialChunkSize = 4096
+
+// SerialPrinter is a printer wired to an RS-232 or virtual COM port. It is
+// a TransportPrinter over the port and is safe for concurrent use; each
+// send is written as one unit. The port has no write timeout, so a
+// cancelled send stops before its next chunk.
+type SerialPrinter struct {
+	*TransportPrinter
+}
+
+// NewSerialPrinter opens port (e.g. "COM3" or "/dev/ttyS0") at baud, 8N1.
+func NewSerialPrinter(port string, baud int, opts ...Option) (*SerialPrinter, error) {
+	mode := &serial.Mode{
+		BaudRate: baud,
+		DataBits: 8,
+		Parity:   serial.NoParity,
+		StopBits: serial.OneStopBit,
+	}
+	sp, err := serial.Open(port, mode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to open serial port %s: %w", ErrNotConnected, port, err)
+	}
+	o := newOptions(opts).with("transport", "serial", "port", port)
+	t := &serialTransport{Port: sp, name: port}
+	p := &SerialPrinter{TransportPrinter: newTransportPrinter(t, o, serialChunkSize)}
+	if err := p.opts.connected(p); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// serialTransport is the port of a SerialPrinter.
+type serialTransport struct {
+	serial.Port
+	name string
+
+	mu       sync.Mutex
+	deadline time.Time // of reads, zero for none
+}
+
+// SetReadDeadline makes reads give up with os.ErrDeadlineExceeded at t.
+func (t *serialTransport) SetReadDeadline(deadline time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.deadline = deadline
+	return nil
+}
+
+// Read reads from the port, with the time left until the read deadline as
+// the port's read timeout.
+func (t *serialTransport) Read(b []byte) (int, error) {
+	t.mu.Lock()
+	deadline := t.deadline
+	t.mu.Unlock()
+	timeout := serial.NoTimeout
+	if !deadline.IsZero() {
+		if timeout = time.Until(deadline); timeout <= 0 {
+			return 0, os.ErrDeadlineExceeded
+		}
+	}
+	if err := t.Port.SetReadTimeout(timeout); err != nil {
+		return 0, fmt.Errorf("serial port %s: %w", t.name, err)
+	}
+	n, err := t.Port.Read(b)
+	if n == 0 && err == nil && !deadline.IsZero() {
+		// The port reports a timeout as an empty read
+		return 0, os.ErrDeadlineExceeded
+	}
+	return n, err
+}
+
+// Flush waits until the port's output buffer has been transmitted.
+func (t *serialTransport) Flush() error {
+	if err := t.Port.Drain(); err != nil {
+		return fmt.Errorf("%w: failed to drain serial port %s: %w", ErrNotConnected, t.name, err)
+	}
+	return nil
+}
+
+// Ping checks that the port handle is still usable.
+func (t *serialTransport) Ping() error {
+	if _, err := t.Port.GetModemStatusBits(); err != nil {
+		return fmt.Errorf("%w: serial port %s: %w", ErrNotConnected, t.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package zpl
+
+import "fmt"
+
+// Ranges accepted by SetDarkness and SetPrintSpeed. Not every printer
+// supports every speed; unsupported ones are clamped by the printer.
+const (
+	MaxDarkness   = 30
+	MinPrintSpeed = 1
+	MaxPrintSpeed = 14
+)
+
+// SetDarkness sets the print darkness with ~SD, from 0 (lightest) to
+// MaxDarkness. It lasts until changed or the printer is reset; save it with
+// the printer's configuration to keep it.
+func SetDarkness(p PrinterConnection, level int) error {
+	if level < 0 || level > MaxDarkness {
+		return fmt.Errorf("invalid darkness %d: must be between 0 and %d", level, MaxDarkness)
+	}
+	return p.SendZPL(fmt.Sprintf("~SD%02d", level))
+}
+
+// SetPrintSpeed sets the print speed in inches per second with ^PR, which
+// also applies to slew and backfeed. It applies to every following label.
+func SetPrintSpeed(p PrinterConnection, ips int) error {
+	if ips < MinPrintSpeed || ips > MaxPrintSpeed {
+		return fmt.Errorf("invalid print speed %d: must be between %d and %d ips", ips, MinPrintSpeed, MaxPrintSpeed)
+	}
+	return p.SendZPL(fmt.Sprintf("^XA^PR%d^XZ", ips))
+}
@@ -0,0 +1,58 @@
+package zpl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetVar reads the Set-Get-Do variable name, such as "media.darkness", and
+// returns its value without the surrounding quotes.
+func GetVar(p PrinterConnection, name string) (string, error) {
+	if err := checkSGD(name); err != nil {
+		return "", err
+	}
+	resp, err := query(p, fmt.Sprintf(`! U1 getvar "%s"`+"\r\n", name), quoted, responseTimeout)
+	if err != nil {
+		return "", err
+	}
+	return unquote(resp)
+}
+
+// SetVar sets the Set-Get-Do variable name to value. The printer does not
+// acknowledge setvar; read the variable back with GetVar to confirm.
+func SetVar(p PrinterConnection, name, value string) error {
+	cmd, err := setVarCommand(name, value)
+	if err != nil {
+		return err
+	}
+	return p.SendZPL(cmd)
+}
+
+// setVarCommand returns the setvar command setting name to value.
+func setVarCommand(name, value string) (string, error) {
+	if err := checkSGD(name); err != nil {
+		return "", err
+	}
+	if err := checkSGD(value); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`! U1 setvar "%s" "%s"`+"\r\n", name, value), nil
+}
+
+// checkSGD rejects strings that would break out of an SGD quoted argument.
+func checkSGD(s string) error {
+	if strings.ContainsAny(s, "\"\r\n") {
+		return fmt.Errorf("invalid SGD argument %q: must not contain quotes or line breaks", s)
+	}
+	return nil
+}
+
+// unquote extracts the value of an SGD response such as "zpl".
+func unquote(resp string) (string, error) {
+	start := strings.Index(resp, `"`)
+	end := strings.LastIndex(resp, `"`)
+	if start < 0 || end <= start {
+		return "", fmt.Errorf("%w: expected a quoted value, got %q", ErrMalformedResponse, resp)
+	}
+	return resp[start+1 : end], nil
+}
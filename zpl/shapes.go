@@ -0,0 +1,41 @@
+package zpl
+
+import "fmt"
+
+// maxGraphicSize is the largest width, height or thickness ^GB accepts.
+const maxGraphicSize = 32000
+
+// Box draws a w by h rectangle outline at (x, y) with lines thickness dots
+// thick, using ^GB. A side shorter than thickness is widened to it by the
+// printer, so Box(x, y, w, h, h) draws a solid bar.
+func (b *LabelBuilder) Box(x, y, w, h, thickness int) *LabelBuilder {
+	return b.graphicBox(x, y, w, h, thickness)
+}
+
+// HLine draws a horizontal line length dots long and thickness dots thick
+// to the right of (x, y).
+func (b *LabelBuilder) HLine(x, y, length, thickness int) *LabelBuilder {
+	return b.graphicBox(x, y, length, thickness, thickness)
+}
+
+// VLine draws a vertical line length dots long and thickness dots thick
+// downwards from (x, y).
+func (b *LabelBuilder) VLine(x, y, length, thickness int) *LabelBuilder {
+	return b.graphicBox(x, y, thickness, length, thickness)
+}
+
+// graphicBox adds a ^GB field after checking its dimensions.
+func (b *LabelBuilder) graphicBox(x, y, w, h, thickness int) *LabelBuilder {
+	for _, d := range []int{w, h, thickness} {
+		if d < 1 || d > maxGraphicSize {
+			b.fail(fmt.Errorf("invalid graphic box %dx%d, thickness %d: sizes must be between 1 and %d", w, h, thickness, maxGraphicSize))
+			return b
+		}
+	}
+	if !b.field(x, y) {
+		return b
+	}
+	fmt.Fprintf(&b.body, "^GB%d,%d,%d^FS\n", w, h, thickness)
+	b.fieldOpen = false
+	return b
+}
@@ -0,0 +1,75 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// closeTimeout bounds how long Close waits for a send in progress.
+const closeTimeout = 30 * time.Second
+
+// shutdown lets Close abort a send in progress once its wait runs out.
+// Sends run under a context derived from it.
+type shutdown struct {
+	ctx   context.Context
+	abort context.CancelFunc
+
+	// closed is done once Close has run.
+	closed     context.Context
+	markClosed context.CancelFunc
+}
+
+func newShutdown() shutdown {
+	ctx, abort := context.WithCancel(context.Background())
+	closed, markClosed := context.WithCancel(context.Background())
+	return shutdown{ctx, abort, closed, markClosed}
+}
+
+// done returns a channel that is closed once the printer is.
+func (s shutdown) done() <-chan struct{} {
+	return s.closed.Done()
+}
+
+// context returns parent, also cancelled when the printer is closed.
+func (s shutdown) context(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	stop := context.AfterFunc(s.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// lock acquires mu, waiting for the send holding it to finish. If ctx is
+// done first, the send is aborted and ctx.Err() returned once mu is held.
+func (s shutdown) lock(ctx context.Context, mu *sync.Mutex) error {
+	locked := make(chan struct{})
+	go func() {
+		mu.Lock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		return nil
+	case <-ctx.Done():
+		s.abort()
+		<-locked
+		return ctx.Err()
+	}
+}
+
+// close runs release once no send holds mu, aborting the send in progress
+// if ctx is done first.
+func (s shutdown) close(ctx context.Context, mu *sync.Mutex, release func() error) error {
+	waitErr := s.lock(ctx, mu)
+	defer mu.Unlock()
+	defer s.markClosed()
+	err := release()
+	if waitErr != nil {
+		return errors.Join(fmt.Errorf("aborted the send in progress: %w", waitErr), err)
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+package zpl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HostStatus is the printer state reported by the ~HS host status command.
+// The fields follow the three response strings in order.
+type HostStatus struct {
+	// String 1
+	CommSettings     int // communication settings bit field
+	PaperOut         bool
+	Paused           bool
+	LabelLength      int // in dots
+	FormatsInBuffer  int // formats waiting in the receive buffer
+	BufferFull       bool
+	CommDiagnostics  bool // communications diagnostic mode is on
+	PartialFormat    bool // a format is partially received
+	CorruptRAM       bool // configuration data was lost
+	UnderTemperature bool
+	OverTemperature  bool
+
+	// String 2
+	FunctionSettings int // function settings bit field
+	HeadOpen         bool
+	RibbonOut        bool
+	ThermalTransfer  bool // false means direct thermal
+	PrintMode        int  // 0 rewind, 1 peel-off, 2 tear-off, 3 cutter, 4 applicator
+	PrintWidthMode   int
+	LabelWaiting     bool // a label is waiting to be taken in peel-off mode
+	LabelsRemaining  int  // labels left in the current batch
+	GraphicsStored   int  // graphic images stored in memory
+
+	// String 3, absent on some firmware
+	Password  int
+	StaticRAM bool // static RAM is installed
+}
+
+// PrinterStatus is the former name of HostStatus.
+type PrinterStatus = HostStatus
+
+// Err returns an error wrapping ErrPrinterFault that lists every condition
+// in s that stops the printer from printing, or nil if there is none. A
+// paused printer is not a fault.
+func (s HostStatus) Err() error {
+	var faults []string
+	for _, f := range []struct {
+		set  bool
+		name string
+	}{
+		{s.PaperOut, "paper out"},
+		{s.RibbonOut, "ribbon out"},
+		{s.HeadOpen, "head open"},
+		{s.OverTemperature, "head over temperature"},
+		{s.UnderTemperature, "head under temperature"},
+		{s.CorruptRAM, "corrupt RAM"},
+		{s.BufferFull, "receive buffer full"},
+	} {
+		if f.set {
+			faults = append(faults, f.name)
+		}
+	}
+	if len(faults) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrPrinterFault, strings.Join(faults, ", "))
+}
+
+// hostStatus sends ~HS to p and parses the reply.
+func hostStatus(p PrinterConnection) (HostStatus, error) {
+	raw, err := query(p, "~HS", frames(3), responseTimeout)
+	if err != nil {
+		return HostStatus{}, err
+	}
+	return ParseHostStatus(raw)
+}
+
+// ParseHostStatus decodes a raw ~HS response, as read from the printer with
+// its STX/ETX framing. The third string is optional.
+func ParseHostStatus(raw string) (HostStatus, error) {
+	var lines [][]string
+	for _, part := range strings.Split(raw, etx) {
+		part = strings.TrimSpace(strings.Trim(strings.TrimSpace(part), stx))
+		if part != "" {
+			lines = append(lines, strings.Split(part, ","))
+		}
+	}
+	if len(lines) < 2 || len(lines[0]) < 12 || len(lines[1]) < 11 {
+		return HostStatus{}, fmt.Errorf("%w: status has too few fields", ErrMalformedResponse)
+	}
+
+	d := statusDecoder{}
+	l1, l2 := lines[0], lines[1]
+	s := HostStatus{
+		CommSettings:     d.int(l1[0], "communication settings"),
+		PaperOut:         l1[1] == "1",
+		Paused:           l1[2] == "1",
+		LabelLength:      d.int(l1[3], "label length"),
+		FormatsInBuffer:  d.int(l1[4], "formats in buffer"),
+		BufferFull:       l1[5] == "1",
+		CommDiagnostics:  l1[6] == "1",
+		PartialFormat:    l1[7] == "1",
+		CorruptRAM:       l1[9] == "1",
+		UnderTemperature: l1[10] == "1",
+		OverTemperature:  l1[11] == "1",
+
+		FunctionSettings: d.int(l2[0], "function settings"),
+		HeadOpen:         l2[2] == "1",
+		RibbonOut:        l2[3] == "1",
+		ThermalTransfer:  l2[4] == "1",
+		PrintMode:        d.int(l2[5], "print mode"),
+		PrintWidthMode:   d.int(l2[6], "print width mode"),
+		LabelWaiting:     l2[7] == "1",
+		LabelsRemaining:  d.int(l2[8], "labels remaining"),
+		GraphicsStored:   d.int(l2[10], "graphics stored"),
+	}
+	if len(lines) > 2 && len(lines[2]) >= 2 {
+		s.Password = d.int(lines[2][0], "password")
+		s.StaticRAM = lines[2][1] == "1"
+	}
+	if d.err != nil {
+		return HostStatus{}, d.err
+	}
+	return s, nil
+}
+
+// statusDecoder parses numeric status fields, keeping the first error.
+type statusDecoder struct {
+	err error
+}
+
+func (d *statusDecoder) int(field, name string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(field))
+	if err != nil && d.err == nil {
+		d.err = fmt.Errorf("%w: %s %q", ErrMalformedResponse, name, field)
+	}
+	return n
+}
+
+// Configuration returns the text of the printer's configuration label, as
+// sent back by ^HH, without printing it. The printer must be able to send
+// data back.
+func Configuration(p PrinterConnection) (string, error) {
+	resp, err := query(p, "^XA^HH^XZ", frames(1), responseTimeout)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(resp), stx+etx), nil
+}
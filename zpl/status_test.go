@@ -0,0 +1,73 @@
+package zpl
+
+import (
+	"errors"
+	"testing"
+)
+
+// Recorded ~HS response strings.
+const (
+	hsLine1 = "\x02030,0,0,1245,000,0,0,0,000,0,0,0\x03\r\n"
+	hsLine2 = "\x02001,0,0,0,1,2,6,0,00000000,1,000\x03\r\n"
+	hsLine3 = "\x021234,0\x03\r\n"
+)
+
+func TestParseHostStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want HostStatus
+	}{
+		{"idle", hsLine1 + hsLine2 + hsLine3, HostStatus{
+			CommSettings: 30, LabelLength: 1245, FunctionSettings: 1,
+			ThermalTransfer: true, PrintMode: 2, PrintWidthMode: 6, Password: 1234,
+		}},
+		{"without the third string", hsLine1 + hsLine2, HostStatus{
+			CommSettings: 30, LabelLength: 1245, FunctionSettings: 1,
+			ThermalTransfer: true, PrintMode: 2, PrintWidthMode: 6,
+		}},
+		{"paper out and paused", "\x02030,1,1,1245,003,0,0,1,000,0,0,0\x03\r\n" +
+			"\x02001,0,1,1,1,2,6,1,00000012,1,004\x03\r\n" + hsLine3, HostStatus{
+			CommSettings: 30, PaperOut: true, Paused: true, LabelLength: 1245, FormatsInBuffer: 3,
+			PartialFormat: true, FunctionSettings: 1, HeadOpen: true, RibbonOut: true,
+			ThermalTransfer: true, PrintMode: 2, PrintWidthMode: 6, LabelWaiting: true,
+			LabelsRemaining: 12, GraphicsStored: 4, Password: 1234,
+		}},
+		{"no line endings", hsLine1[:len(hsLine1)-2] + hsLine2[:len(hsLine2)-2], HostStatus{
+			CommSettings: 30, LabelLength: 1245, FunctionSettings: 1,
+			ThermalTransfer: true, PrintMode: 2, PrintWidthMode: 6,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHostStatus(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHostStatus(%q) =\n%+v\nwant\n%+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHostStatusMalformed(t *testing.T) {
+	tests := []struct {
+		name, raw string
+	}{
+		{"empty", ""},
+		{"one string", hsLine1},
+		{"truncated", hsLine1 + "\x02001,0,0,0,1,2"},
+		{"short first string", "\x02030,0,0,1245\x03\r\n" + hsLine2},
+		{"not a number", "\x02030,0,0,abc,000,0,0,0,000,0,0,0\x03\r\n" + hsLine2},
+		{"bad third string", hsLine1 + hsLine2 + "\x02x,0\x03\r\n"},
+		{"not a status", "\x02PRINTER READY\x03\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseHostStatus(tt.raw); !errors.Is(err, ErrMalformedResponse) {
+				t.Errorf("ParseHostStatus(%q): got %v, want ErrMalformedResponse", tt.raw, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// teePrinter is the decorator returned by Tee.
+type teePrinter struct {
+	PrinterConnection
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// wireSender is implemented by the printers of this package, whose sends
+// copy what they write to the transport to the tap of their context.
+type wireSender interface {
+	sendZPLN(ctx context.Context, zpl string) (int, error)
+	sendRaw(ctx context.Context, data []byte) (int, error)
+	sendReader(ctx context.Context, r io.Reader) error
+}
+
+// Tee wraps p so that every payload sent through it is also written to w,
+// such as a capture file for ReplayFile. Queries such as Status go
+// straight to p and are not captured. A failure to write to w is
+// returned, joined with the send's error, but does not stop the send.
+//
+// When p is one of the printers of this package, such as a NetworkPrinter
+// or a TransportPrinter, the capture is taken on the wire: the bytes
+// written to the transport, after the prolog, encoding, validation and
+// newline of p's options, so that ReplayFile reproduces them exactly. A
+// send that fails is captured as far as it got, and a send retried on
+// reconnect once per attempt, along with the setup repeated on the new
+// connection.
+//
+// Any other connection, including p wrapped by WithRetry or Buffered, is
+// captured before the send instead: ZPL payloads as given with the
+// trailing newline the send adds, raw sends and streams byte for byte. The
+// options of the printer beneath are then not applied to the capture, and
+// ReplayFile does not apply them either, so such a capture of a printer
+// with WithProlog or WithEncoding replays without them.
+func Tee(p PrinterConnection, w io.Writer) PrinterConnection {
+	return &teePrinter{PrinterConnection: p, w: w}
+}
+
+func (t *teePrinter) SendZPL(zpl string) error {
+	return t.SendZPLContext(context.Background(), zpl)
+}
+
+func (t *teePrinter) SendZPLN(zpl string) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p, ok := t.PrinterConnection.(wireSender); ok {
+		return t.onWire(context.Background(), func(ctx context.Context) (int, error) {
+			return p.sendZPLN(ctx, zpl)
+		})
+	}
+	cerr := t.capture([]byte(withNewline(zpl)))
+	n, err := t.PrinterConnection.SendZPLN(zpl)
+	return n, errors.Join(err, cerr)
+}
+
+func (t *teePrinter) SendZPLContext(ctx context.Context, zpl string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p, ok := t.PrinterConnection.(wireSender); ok {
+		_, err := t.onWire(ctx, func(ctx context.Context) (int, error) {
+			return p.sendZPLN(ctx, zpl)
+		})
+		return err
+	}
+	cerr := t.capture([]byte(withNewline(zpl)))
+	return errors.Join(t.PrinterConnection.SendZPLContext(ctx, zpl), cerr)
+}
+
+func (t *teePrinter) SendZPLReader(r io.Reader) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p, ok := t.PrinterConnection.(wireSender); ok {
+		_, err := t.onWire(context.Background(), func(ctx context.Context) (int, error) {
+			return 0, p.sendReader(ctx, r)
+		})
+		return err
+	}
+	cw := &captureWriter{w: t.w}
+	err := t.PrinterConnection.SendZPLReader(io.TeeReader(r, cw))
+	if cw.err == nil && cw.last != '\n' {
+		cw.Write([]byte("\n"))
+	}
+	return errors.Join(err, cw.error())
+}
+
+func (t *teePrinter) SendRaw(data []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p, ok := t.PrinterConnection.(wireSender); ok {
+		return t.onWire(context.Background(), func(ctx context.Context) (int, error) {
+			return p.sendRaw(ctx, data)
+		})
+	}
+	cerr := t.capture(data)
+	n, err := t.PrinterConnection.SendRaw(data)
+	return n, errors.Join(err, cerr)
+}
+
+func (t *teePrinter) RawSend(data []byte) error {
+	_, err := t.SendRaw(data)
+	return err
+}
+
+func (t *teePrinter) unwrap() PrinterConnection {
+	return t.PrinterConnection
+}
+
+// onWire runs send with a context tapped by a capture writer, so that the
+// printer captures what it writes. The caller holds t.mu.
+func (t *teePrinter) onWire(ctx context.Context, send func(ctx context.Context) (int, error)) (int, error) {
+	cw := &captureWriter{w: t.w}
+	n, err := send(withTap(ctx, cw))
+	return n, errors.Join(err, cw.error())
+}
+
+// capture writes data to the capture writer. The caller holds t.mu.
+func (t *teePrinter) capture(data []byte) error {
+	if _, err := t.w.Write(data); err != nil {
+		return fmt.Errorf("failed to capture payload: %w", err)
+	}
+	return nil
+}
+
+// captureWriter copies a stream to w, remembering its last byte and the
+// first write error. It never fails itself, so that a broken capture does
+// not interrupt the stream to the printer.
+type captureWriter struct {
+	w    io.Writer
+	last byte
+	err  error
+}
+
+// error returns the first write error, if any, as a capture failure.
+func (c *captureWriter) error() error {
+	if c.err != nil {
+		return fmt.Errorf("failed to capture payload: %w", c.err)
+	}
+	return nil
+}
+
+func (c *captureWriter) Write(b []byte) (int, error) {
+	if len(b) > 0 {
+		c.last = b[len(b)-1]
+	}
+	if c.err == nil {
+		_, c.err = c.w.Write(b)
+	}
+	return len(b), nil
+}
+
+// ReplayFile sends a capture written by Tee back to p byte for byte with
+// SendRaw, so the options of p do not apply to it. A capture taken on the
+// wire already holds what they added. Failing to read the file returns an
+// error wrapping the *fs.PathError, as with SendFile.
+func ReplayFile(p PrinterConnection, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read capture file: %w", err)
+	}
+	_, err = p.SendRaw(data)
+	return err
+}
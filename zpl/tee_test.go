@@ -0,0 +1,57 @@
+package zpl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// bufTransport is a write-only Transport that keeps what is written to it.
+type bufTransport struct {
+	bytes.Buffer
+}
+
+func (b *bufTransport) Read([]byte) (int, error) { return 0, ErrNotBidirectional }
+
+func (b *bufTransport) Close() error { return nil }
+
+func TestTeeCapturesWire(t *testing.T) {
+	wire := new(bufTransport)
+	p, err := NewTransportPrinter(wire, WithProlog("^PW812"), WithEncoding(EncodingLatin1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var capture bytes.Buffer
+	tee := Tee(p, &capture)
+	if err := tee.SendZPL("^XA^FDcafé^FS^XZ"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tee.SendRaw([]byte("~HS")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tee.SendZPLReader(strings.NewReader("^xa^XZ")); err != nil {
+		t.Fatal(err)
+	}
+	want := "^XA^CI27^PW812^FDcaf\xe9^FS^XZ\n~HS^xa^XZ\n"
+	if got := wire.String(); got != want {
+		t.Fatalf("wire got %q, want %q", got, want)
+	}
+	if got := capture.String(); got != want {
+		t.Errorf("capture got %q, want the wire bytes %q", got, want)
+	}
+}
+
+func TestTeeCapturesBeforeSend(t *testing.T) {
+	m := NewMockPrinter()
+	var capture bytes.Buffer
+	tee := Tee(m, &capture)
+	if err := tee.SendZPL("^XA^XZ"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tee.SendZPLReader(strings.NewReader("^XA^XZ")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := capture.String(), "^XA^XZ\n^XA^XZ\n"; got != want {
+		t.Errorf("capture got %q, want %q", got, want)
+	}
+}
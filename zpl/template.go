@@ -0,0 +1,37 @@
+package zpl
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Template is a label layout with text/template placeholders such as
+// {{.SKU}}, parsed once and rendered per item.
+//
+// Carets, tildes and single braces in the ZPL need no escaping; only a
+// literal "{{" must be written as {{"{{"}}. A field used by the template
+// but missing from the data is an error rather than "<no value>".
+type Template struct {
+	tmpl *template.Template
+}
+
+// NewTemplate parses zpl as a label template.
+func NewTemplate(zpl string) (*Template, error) {
+	t, err := template.New("label").Option("missingkey=error").Parse(zpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label template: %w", err)
+	}
+	return &Template{tmpl: t}, nil
+}
+
+// Render executes the template with data, usually a struct or a map, and
+// returns the resulting ZPL. Values are inserted verbatim, so a value
+// containing ^ or ~ is read by the printer as a command.
+func (t *Template) Render(data any) (string, error) {
+	var s strings.Builder
+	if err := t.tmpl.Execute(&s, data); err != nil {
+		return "", fmt.Errorf("failed to render label template: %w", err)
+	}
+	return s.String(), nil
+}
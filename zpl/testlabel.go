@@ -0,0 +1,24 @@
+package zpl
+
+import "fmt"
+
+// Text and barcode of the test label when TestLabel is given none.
+const (
+	DefaultTestLabelTitle   = "Hello from Go!"
+	DefaultTestLabelBarcode = "123456789"
+)
+
+// TestLabel returns the ZPL of a small label with title in font 0 above a
+// Code 128 barcode of barcode, the label the CLI prints to check a
+// connection. Empty arguments use DefaultTestLabelTitle and
+// DefaultTestLabelBarcode. Both are used as field data unchanged, so they
+// must not contain ^ or ~.
+func TestLabel(title, barcode string) string {
+	if title == "" {
+		title = DefaultTestLabelTitle
+	}
+	if barcode == "" {
+		barcode = DefaultTestLabelBarcode
+	}
+	return fmt.Sprintf("^XA\n^FO20,20^A0N,30,30^FD%s^FS\n^FO20,60^BY2^BCN,60,Y,N,N^FD%s^FS\n^XZ\n", title, barcode)
+}
@@ -0,0 +1,186 @@
+package zpl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Canned replies of a TestServer until changed.
+const (
+	DefaultTestHostStatus = stx + "030,0,0,1218,000,0,0,0,000,0,0,0" + etx + "\r\n" +
+		stx + "000,0,0,0,0,2,6,0,00000000,1,000" + etx + "\r\n" +
+		stx + "1234,0" + etx + "\r\n"
+	DefaultTestHostIdentification = stx + "ZTEST,V1.0.0,8,8192KB" + etx + "\r\n"
+)
+
+// testServerDrain is how long Close keeps reading open connections.
+const testServerDrain = 100 * time.Millisecond
+
+// TestServer is a fake network printer on a loopback port, for testing code
+// that uses NetworkPrinter without hardware. It records everything it
+// receives and answers ~HS and ~HI with canned replies.
+type TestServer struct {
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu       sync.Mutex
+	received bytes.Buffer
+	status   string
+	ident    string
+	conns    map[net.Conn]bool
+}
+
+// NewTestServer starts a TestServer on a free loopback port.
+func NewTestServer() (*TestServer, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start test server: %w", err)
+	}
+	s := &TestServer{
+		ln:     ln,
+		status: DefaultTestHostStatus,
+		ident:  DefaultTestHostIdentification,
+		conns:  map[net.Conn]bool{},
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the host:port to pass to NewNetworkPrinter.
+func (s *TestServer) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// SetHostStatus replaces the reply to ~HS, which must be framed the way a
+// printer frames it.
+func (s *TestServer) SetHostStatus(raw string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = raw
+}
+
+// SetHostIdentification replaces the reply to ~HI.
+func (s *TestServer) SetHostIdentification(raw string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ident = raw
+}
+
+// Received returns everything received so far, over all connections. Data
+// arrives asynchronously: close the server, or make a query such as Ping
+// after the sends, before checking it.
+func (s *TestServer) Received() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received.String()
+}
+
+// Formats returns the ^XA...^XZ formats received so far, in order.
+func (s *TestServer) Formats() []string {
+	data := s.Received()
+	var formats []string
+	for {
+		start := indexFold(data, "^XA", 0)
+		if start < 0 {
+			return formats
+		}
+		end := indexFold(data, "^XZ", start)
+		if end < 0 {
+			return formats
+		}
+		end += 3
+		formats = append(formats, data[start:end])
+		data = data[end:]
+	}
+}
+
+// Reset forgets what has been received.
+func (s *TestServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received.Reset()
+}
+
+// Close stops the server. Data already sent by clients is still read and
+// recorded before their connections are dropped.
+func (s *TestServer) Close() error {
+	err := s.ln.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		c.SetReadDeadline(time.Now().Add(testServerDrain))
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+func (s *TestServer) serve() {
+	defer s.wg.Done()
+	for {
+		c, err := s.ln.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+		if err != nil {
+			continue
+		}
+		s.mu.Lock()
+		s.conns[c] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.handle(c)
+	}
+}
+
+// handle records what c sends and answers the queries in it. Commands are
+// looked for across reads, so a query split over two packets is answered.
+func (s *TestServer) handle(c net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.Close()
+	}()
+
+	var stream []byte
+	scanned := 0
+	buf := make([]byte, 4096)
+	for {
+		n, err := c.Read(buf)
+		if n > 0 {
+			stream = append(stream, buf[:n]...)
+			s.mu.Lock()
+			s.received.Write(buf[:n])
+			s.mu.Unlock()
+
+			for ; scanned+3 <= len(stream); scanned++ {
+				if reply := s.reply(stream, scanned); reply != "" {
+					c.Write([]byte(reply))
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// reply returns the answer to the query starting at offset i of stream, if
+// there is one there.
+func (s *TestServer) reply(stream []byte, i int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case hasFoldAt(stream, i, "~HS"):
+		return s.status
+	case hasFoldAt(stream, i, "~HI"):
+		return s.ident
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package zpl
+
+import (
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTestServerFormats(t *testing.T) {
+	s, err := NewTestServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewNetworkPrinter(s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	p.SendRaw([]byte("~JA^XA^FDcaf\xe9^FS^XZ\n^xa^FDſſſ^FS^xz^XA^FD"))
+	s.Close()
+	want := []string{"^XA^FDcaf\xe9^FS^XZ", "^xa^FDſſſ^FS^xz"}
+	if got := s.Formats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTestServerReplies(t *testing.T) {
+	s, err := NewTestServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	s.SetHostIdentification(stx + "ZD420,V84.20.18Z,8,8176KB" + etx + "\r\n")
+	c, err := net.Dial("tcp", s.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"status", []string{"~HS"}, DefaultTestHostStatus},
+		{"lower case", []string{"~hi\n"}, stx + "ZD420,V84.20.18Z,8,8176KB" + etx + "\r\n"},
+		{"split over writes", []string{"^XA^XZ~", "H", "S"}, DefaultTestHostStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, w := range tt.writes {
+				if _, err := c.Write([]byte(w)); err != nil {
+					t.Fatal(err)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			c.SetReadDeadline(time.Now().Add(time.Second))
+			got := make([]byte, len(tt.want))
+			if _, err := io.ReadFull(c, got); err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
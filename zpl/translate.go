@@ -0,0 +1,62 @@
+package zpl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Translate shifts every ^FO and ^FT field origin in zpl by dx, dy dots,
+// leaving everything else, field data included, unchanged. It fails if a
+// shifted origin would be negative. Omitted coordinates count as 0, as on
+// the printer. Commands are found with ParseZPL, so prefix changes are
+// followed and the binary data of downloads is never taken for commands.
+func Translate(zpl string, dx, dy int) (string, error) {
+	cmds := ParseZPL(zpl)
+	var b strings.Builder
+	b.Grow(len(zpl))
+	last := 0
+	for i, c := range cmds {
+		if c.Prefix != '^' || c.Code != "FO" && c.Code != "FT" {
+			continue
+		}
+		// The parameters run up to the next command
+		start, end := c.Offset+1+len(c.Code), len(zpl)
+		if i+1 < len(cmds) {
+			end = cmds[i+1].Offset
+		}
+		params, err := translateOrigin(zpl[start:end], dx, dy)
+		if err != nil {
+			return "", fmt.Errorf("^%s at offset %d: %w", c.Code, c.Offset, err)
+		}
+		b.WriteString(zpl[last:start])
+		b.WriteString(params)
+		last = end
+	}
+	b.WriteString(zpl[last:])
+	return b.String(), nil
+}
+
+// translateOrigin shifts the x and y of the ^FO or ^FT parameters params,
+// keeping any further parameters and trailing whitespace.
+func translateOrigin(params string, dx, dy int) (string, error) {
+	trimmed := strings.TrimRight(params, " \t\r\n")
+	args := strings.Split(trimmed, ",")
+	for len(args) < 2 {
+		args = append(args, "")
+	}
+	for i, d := range []int{dx, dy} {
+		v := 0
+		if s := strings.TrimSpace(args[i]); s != "" {
+			var err error
+			if v, err = strconv.Atoi(s); err != nil {
+				return "", fmt.Errorf("invalid coordinate %q", s)
+			}
+		}
+		if v += d; v < 0 {
+			return "", fmt.Errorf("shifted coordinate %d is negative", v)
+		}
+		args[i] = strconv.Itoa(v)
+	}
+	return strings.Join(args, ",") + params[len(trimmed):], nil
+}
@@ -0,0 +1,40 @@
+package zpl
+
+import "testing"
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name   string
+		zpl    string
+		dx, dy int
+		want   string
+	}{
+		{"origins", "^XA^FO10,20^FDa^FS^FT5,5,1^FDb^FS^XZ", 3, 4, "^XA^FO13,24^FDa^FS^FT8,9,1^FDb^FS^XZ"},
+		{"whitespace kept", "^XA\n^FO10,20 \n^FS\n^XZ", 1, 1, "^XA\n^FO11,21 \n^FS\n^XZ"},
+		{"omitted coordinates", "^XA^FO^FS^fo,7^FS^XZ", 2, 2, "^XA^FO2,2^FS^fo2,9^FS^XZ"},
+		{"field data kept", "^XA^FO1,1^FDFO1,1 ~FO1,1^FS^XZ", 1, 1, "^XA^FO2,2^FDFO1,1 ~FO1,1^FS^XZ"},
+		{"binary graphic", "^XA^FO0,0^GFB,6,6,1,^FO1,1^FS^XZ", 5, 5, "^XA^FO5,5^GFB,6,6,1,^FO1,1^FS^XZ"},
+		{"binary font", "~DYR:F,B,T,7,,^FO1,1^XA^FO1,1^XZ", 1, 0, "~DYR:F,B,T,7,,^FO1,1^XA^FO2,1^XZ"},
+		{"prefix changed", "^XA^CC+\n+FO1,1+FS+XZ", 1, 1, "^XA^CC+\n+FO2,2+FS+XZ"},
+		{"negative shift", "^XA^FO10,10^FS^XZ", -10, -5, "^XA^FO0,5^FS^XZ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Translate(tt.zpl, tt.dx, tt.dy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Translate(%q, %d, %d) = %q, want %q", tt.zpl, tt.dx, tt.dy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	for _, zpl := range []string{"^XA^FO5,5^FS^XZ", "^XA^FOx,5^FS^XZ"} {
+		if _, err := Translate(zpl, -10, 0); err == nil {
+			t.Errorf("Translate(%q, -10, 0): no error", zpl)
+		}
+	}
+}
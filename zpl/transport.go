@@ -0,0 +1,495 @@
+package zpl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// transportChunkSize is how much is written to a Transport between
+// cancellation checks.
+const transportChunkSize = 32 * 1024
+
+// Transport is a byte stream to a printer, for backends the package does
+// not cover such as a print server SDK. Wrap one with NewTransportPrinter
+// to get a PrinterConnection.
+//
+// A transport that can also bound reads should implement
+// SetReadDeadline(time.Time) error; otherwise queries such as Status block
+// in Read until the printer answers. A write-only transport returns an
+// error from Read. Close should make a Read in progress return. Optional
+// methods are used when present: WriteContext(context.Context, []byte)
+// (int, error) to abort a write in progress, Flush() error to push out
+// buffered data and Ping() error to check the link. The printers of this
+// package are transports too.
+type Transport interface {
+	Write(b []byte) (int, error)
+	Read(b []byte) (int, error)
+	Close() error
+}
+
+// TransportPrinter is a PrinterConnection over a Transport. It is safe for
+// concurrent use; each send is written as one unit. NetworkPrinter,
+// USBPrinter and SerialPrinter are TransportPrinters over the socket, the
+// USB endpoints and the port, so they share its methods.
+type TransportPrinter struct {
+	mu    sync.Mutex
+	t     Transport // nil once closed, or after a failed reconnect
+	opts  options
+	chunk int // bytes written between cancellation checks
+
+	closing   shutdown
+	deadlines deadlines
+
+	// alerts reads all input once AlertChannel has been called
+	alerts atomic.Pointer[alertReader]
+
+	// redial, if set, replaces a transport that failed
+	redial *redialer
+}
+
+// redialer is how a printer replaces a transport whose send failed, such
+// as a dropped socket or an unplugged USB device.
+type redialer struct {
+	// open returns a new transport to the same printer.
+	open func() (Transport, error)
+	// retries is how often a failed send is retried on a new transport,
+	// waiting delay before the first retry and doubling it each time.
+	retries int
+	delay   time.Duration
+	// retryable reports whether a send that failed with err is retried.
+	retryable func(err error) bool
+	// raw makes SendRaw retry too. Streams never are, since the reader
+	// cannot be rewound.
+	raw bool
+}
+
+// NewTransportPrinter returns a printer that sends over t. The printer owns
+// t and closes it in Close.
+func NewTransportPrinter(t Transport, opts ...Option) (*TransportPrinter, error) {
+	o := newOptions(opts).with("transport", fmt.Sprintf("%T", t))
+	p := newTransportPrinter(t, o, transportChunkSize)
+	if err := p.opts.connected(p); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// newTransportPrinter returns a printer over t that writes chunk bytes at a
+// time, without running the setup of opts.
+func newTransportPrinter(t Transport, opts options, chunk int) *TransportPrinter {
+	return &TransportPrinter{t: t, opts: opts, chunk: chunk, closing: newShutdown()}
+}
+
+// SendZPL writes zpl to the transport, adding a trailing newline if
+// missing.
+func (p *TransportPrinter) SendZPL(zpl string) error {
+	return p.SendZPLContext(context.Background(), zpl)
+}
+
+// SendZPLContext writes zpl to the transport in chunks. Cancelling ctx
+// stops the send before the next chunk, or aborts the write in progress
+// on transports that support it. A printer that reconnects, such as a
+// NetworkPrinter with WithReconnect, retries a failed write on a new
+// connection.
+func (p *TransportPrinter) SendZPLContext(ctx context.Context, zpl string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.send(ctx, zpl)
+	return err
+}
+
+// SendZPLN is like SendZPL but also returns the number of bytes written,
+// including the added newline. A short write is an error.
+func (p *TransportPrinter) SendZPLN(zpl string) (int, error) {
+	return p.sendZPLN(context.Background(), zpl)
+}
+
+// sendZPLN implements SendZPLN with a context, which may carry a tap.
+func (p *TransportPrinter) sendZPLN(ctx context.Context, zpl string) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.send(ctx, zpl)
+}
+
+// send implements SendZPLContext, returning the bytes written by the last
+// attempt. The caller holds p.mu.
+func (p *TransportPrinter) send(ctx context.Context, zpl string) (int, error) {
+	zpl, err := p.opts.prepare(zpl)
+	if err != nil {
+		return 0, err
+	}
+	n, err := p.output(ctx, []byte(zpl), countFormats(zpl), true)
+	return int(n), err
+}
+
+// prepare applies the options of the printer to zpl, as the ZPL sends do.
+func (p *TransportPrinter) prepare(zpl string) (string, error) {
+	return p.opts.prepare(zpl)
+}
+
+// RawSend writes data to the transport unchanged.
+func (p *TransportPrinter) RawSend(data []byte) error {
+	_, err := p.SendRaw(data)
+	return err
+}
+
+// Write is SendRaw, so that the printer can serve as a Transport.
+func (p *TransportPrinter) Write(b []byte) (int, error) {
+	return p.SendRaw(b)
+}
+
+// SendRaw is like RawSend but also returns the number of bytes written.
+func (p *TransportPrinter) SendRaw(data []byte) (int, error) {
+	return p.sendRaw(context.Background(), data)
+}
+
+// sendRaw implements SendRaw with a context, which may carry a tap.
+func (p *TransportPrinter) sendRaw(ctx context.Context, data []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	retry := p.redial != nil && p.redial.raw
+	n, err := p.output(ctx, data, countFormats(string(data)), retry)
+	return int(n), err
+}
+
+// output writes data, holding labels formats, and reports the send to the
+// metrics and the log. With retry set, a failed write is retried on a new
+// transport as the printer's redialer allows. The caller holds p.mu.
+func (p *TransportPrinter) output(ctx context.Context, data []byte, labels int, retry bool) (n int64, err error) {
+	ctx, cancel := p.closing.context(ctx)
+	defer cancel()
+	ctx, cancelDeadline := p.deadlines.writeContext(ctx)
+	defer cancelDeadline()
+	start := time.Now()
+	defer func() { p.opts.observeSend(start, n, labels, err) }()
+
+	n, err = p.write(ctx, data)
+	r := p.redial
+	if !retry || r == nil {
+		return n, err
+	}
+	delay := r.delay
+	for attempt := 1; err != nil && attempt <= r.retries && r.retryable(err); attempt++ {
+		select {
+		case <-p.closing.done():
+			return n, err
+		default:
+		}
+		if ctx.Err() != nil {
+			return n, ctx.Err()
+		}
+
+		// Back off before reconnecting
+		p.opts.log().Warn("send failed, reconnecting", "attempt", attempt, "delay", delay, "err", err)
+		select {
+		case <-ctx.Done():
+			return n, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+
+		n = 0
+		if err = p.reconnect(ctx); err == nil {
+			n, err = p.write(ctx, data)
+		}
+		if err != nil && attempt == r.retries {
+			return n, fmt.Errorf("gave up after %d attempts: %w", attempt+1, err)
+		}
+	}
+	return n, err
+}
+
+// write makes a single attempt at writing data to the current transport,
+// in chunks of p.chunk bytes. A chunk written in part is followed by one
+// for the rest; only a write of nothing fails, with io.ErrShortWrite. The
+// caller holds p.mu. What reaches the transport is copied to the tap of
+// ctx, if it has one.
+func (p *TransportPrinter) write(ctx context.Context, data []byte) (int64, error) {
+	if err := p.checkOpen(); err != nil {
+		return 0, err
+	}
+	tap, _ := ctx.Value(tapKey{}).(io.Writer)
+	var sent int64
+	for len(data) > 0 {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		chunk := data[:min(len(data), p.chunk)]
+		n, err := p.writeChunk(ctx, chunk)
+		sent += int64(n)
+		if tap != nil && n > 0 {
+			tap.Write(chunk[:n])
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			return sent, fmt.Errorf("%w: failed to send ZPL: %w", ErrNotConnected, err)
+		}
+		if n == 0 {
+			return sent, fmt.Errorf("%w: failed to send ZPL: wrote %d of %d bytes: %w",
+				ErrNotConnected, sent, sent+int64(len(data)), io.ErrShortWrite)
+		}
+		data = data[n:]
+	}
+	return sent, nil
+}
+
+// tapKey is the context key of the writer that sends copy the bytes they
+// write to the transport to, see withTap.
+type tapKey struct{}
+
+// withTap returns ctx making the sends of a TransportPrinter copy every
+// byte written to the transport to w, whose errors are ignored.
+func withTap(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, tapKey{}, w)
+}
+
+// writeChunk writes b with the transport's WriteContext if it has one.
+func (p *TransportPrinter) writeChunk(ctx context.Context, b []byte) (int, error) {
+	if w, ok := p.t.(interface {
+		WriteContext(context.Context, []byte) (int, error)
+	}); ok {
+		return w.WriteContext(ctx, b)
+	}
+	return p.t.Write(b)
+}
+
+// reconnect replaces the transport with a new one from the redialer and
+// repeats the setup of the options on it. The caller holds p.mu.
+func (p *TransportPrinter) reconnect(ctx context.Context) error {
+	if err := p.replace(); err != nil {
+		return err
+	}
+	return p.opts.setup(func(zpl string) error {
+		zpl, err := p.opts.prepare(zpl)
+		if err != nil {
+			return err
+		}
+		_, err = p.write(ctx, []byte(zpl))
+		return err
+	})
+}
+
+// replace closes the transport and opens a new one from the redialer. A
+// printer shut with Close stays shut. The caller holds p.mu.
+func (p *TransportPrinter) replace() error {
+	select {
+	case <-p.closing.done():
+		return fmt.Errorf("%w: printer is closed", ErrNotConnected)
+	default:
+	}
+	p.release()
+	t, err := p.redial.open()
+	if err != nil {
+		return err
+	}
+	p.t = t
+	return nil
+}
+
+// release closes the transport, which also stops the alert reader reading
+// it. It is safe to call more than once. The caller holds p.mu.
+func (p *TransportPrinter) release() error {
+	if p.t == nil {
+		return nil
+	}
+	p.alerts.Store(nil)
+	err := p.t.Close()
+	p.t = nil
+	return err
+}
+
+// checkOpen fails once the printer is closed, or after a failed reconnect.
+// The caller holds p.mu.
+func (p *TransportPrinter) checkOpen() error {
+	if p.t == nil {
+		return fmt.Errorf("%w: connection is closed", ErrNotConnected)
+	}
+	return nil
+}
+
+// SendZPLReader streams r to the transport. Streams are not retried on
+// reconnect, since the reader cannot be rewound.
+func (p *TransportPrinter) SendZPLReader(r io.Reader) error {
+	return p.sendReader(context.Background(), r)
+}
+
+// sendReader implements SendZPLReader with a context, which may carry a
+// tap.
+func (p *TransportPrinter) sendReader(ctx context.Context, r io.Reader) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ctx, cancel := p.closing.context(ctx)
+	defer cancel()
+	ctx, cancelDeadline := p.deadlines.writeContext(ctx)
+	defer cancelDeadline()
+	start := time.Now()
+	var formats formatCounter
+	n, err := streamZPL(func(b []byte) (int, error) {
+		n, err := p.write(ctx, b)
+		return int(n), err
+	}, io.TeeReader(r, &formats), p.chunk)
+	p.opts.observeSend(start, n, formats.n, err)
+	return err
+}
+
+// Flush waits for any send in progress to finish, then for the transport
+// to push out the data it buffers, if it has a Flush method.
+func (p *TransportPrinter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.checkOpen(); err != nil {
+		return err
+	}
+	if f, ok := p.t.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Read reads data the printer sent back through the transport, giving up
+// at the read deadline on transports that can bound reads. Read is not
+// serialized with sends; use Status or GetVar for request-response
+// exchanges.
+func (p *TransportPrinter) Read(b []byte) (int, error) {
+	if a := p.alerts.Load(); a != nil {
+		return a.read(b, p.deadlines.readBy())
+	}
+	// A reconnect swaps p.t under p.mu; the read itself does not hold it
+	p.mu.Lock()
+	t, err := p.t, p.checkOpen()
+	p.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	return readBy(t, b, p.deadlines.readBy())
+}
+
+// readBy reads from t into b, giving up at deadline if t can bound reads.
+// A zero deadline waits for as long as it takes.
+func readBy(t Transport, b []byte, deadline time.Time) (int, error) {
+	if d, ok := t.(interface{ SetReadDeadline(time.Time) error }); ok {
+		if err := d.SetReadDeadline(deadline); err != nil {
+			return 0, err
+		}
+		if !deadline.IsZero() {
+			defer d.SetReadDeadline(time.Time{})
+		}
+	}
+	return t.Read(b)
+}
+
+// acquire locks p for a query until the returned function is called.
+func (p *TransportPrinter) acquire() func() {
+	p.mu.Lock()
+	return p.mu.Unlock
+}
+
+// readDeadline reads a reply into b by deadline; the caller holds p.mu.
+func (p *TransportPrinter) readDeadline(b []byte, deadline time.Time) (int, error) {
+	if a := p.alerts.Load(); a != nil {
+		return a.read(b, deadline)
+	}
+	if err := p.checkOpen(); err != nil {
+		return 0, err
+	}
+	return readBy(p.t, b, deadline)
+}
+
+// closed returns a channel that is closed once Close is called.
+func (p *TransportPrinter) closed() <-chan struct{} {
+	return p.closing.done()
+}
+
+// alertReader returns the reader behind AlertChannel, starting it if needed.
+func (p *TransportPrinter) alertReader() (*alertReader, error) {
+	if a := p.alerts.Load(); a != nil {
+		return a, nil
+	}
+	if err := p.checkOpen(); err != nil {
+		return nil, err
+	}
+	t := p.t
+	if d, ok := t.(interface{ SetReadDeadline(time.Time) error }); ok {
+		if err := d.SetReadDeadline(time.Time{}); err != nil {
+			return nil, err
+		}
+	}
+	a := newAlertReader(t.Read, p.opts)
+	p.alerts.Store(a)
+	return a, nil
+}
+
+// SetDeadline sets the read and write deadlines, see SetReadDeadline and
+// SetWriteDeadline.
+func (p *TransportPrinter) SetDeadline(t time.Time) error {
+	p.deadlines.set(t)
+	return nil
+}
+
+// SetReadDeadline makes Read give up with os.ErrDeadlineExceeded at t, on
+// transports that can bound reads. A zero t clears the deadline.
+func (p *TransportPrinter) SetReadDeadline(t time.Time) error {
+	p.deadlines.setRead(t)
+	return nil
+}
+
+// SetWriteDeadline bounds the sends that start afterwards, including any
+// reconnect attempts: a send still running at t stops before its next
+// chunk, or has its write aborted on transports that support it, and fails
+// with context.DeadlineExceeded. A zero t clears the deadline.
+func (p *TransportPrinter) SetWriteDeadline(t time.Time) error {
+	p.deadlines.setWrite(t)
+	return nil
+}
+
+// SentCount returns the number of label formats sent successfully since
+// the printer was opened, counting each ^XZ once. It is kept on the host
+// and includes formats the printer may have refused.
+func (p *TransportPrinter) SentCount() uint64 {
+	return p.opts.stats.labels.Load()
+}
+
+// Status queries the printer with ~HS and reads the reply from the
+// transport.
+func (p *TransportPrinter) Status() (HostStatus, error) {
+	return hostStatus(p)
+}
+
+// Ping calls the transport's Ping method if it has one, and otherwise
+// only checks that the printer is open.
+func (p *TransportPrinter) Ping() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.checkOpen(); err != nil {
+		return err
+	}
+	if pinger, ok := p.t.(interface{ Ping() error }); ok {
+		return pinger.Ping()
+	}
+	return nil
+}
+
+// Close closes the transport once any send in progress has finished,
+// aborting the send after 30 seconds.
+func (p *TransportPrinter) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	return p.CloseContext(ctx)
+}
+
+// CloseContext is like Close but waits for a send in progress only until
+// ctx is done.
+func (p *TransportPrinter) CloseContext(ctx context.Context) error {
+	return p.closing.close(ctx, &p.mu, func() error {
+		err := p.release()
+		p.opts.logClose(err)
+		return err
+	})
+}
@@ -0,0 +1,51 @@
+package zpl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Unit is a ^MU unit of measure for field positions and sizes.
+type Unit byte
+
+// Units accepted by LabelBuilder.Units.
+const (
+	Dots        Unit = 'D'
+	Inches      Unit = 'I'
+	Millimeters Unit = 'M'
+)
+
+// errUnitsMM is recorded when a millimeter method, which converts to dots,
+// is used while the builder's units are not dots.
+var errUnitsMM = errors.New("millimeter methods need dot units: use plain coordinates with Units(Millimeters)")
+
+// Units makes the coordinates and sizes of every field, from this format
+// on, count in u instead of dots, so that Text(10, 10, ...) after
+// Units(Millimeters) is 10mm from the label home.
+//
+// ^MU only affects commands that follow it and stays in effect on the
+// printer across formats, so the builder places it after the setup
+// commands and switches back to dots before each ^XZ. Width, Length and
+// Home therefore stay in dots.
+func (b *LabelBuilder) Units(u Unit) *LabelBuilder {
+	switch u {
+	case Dots, Inches, Millimeters:
+	default:
+		b.fail(fmt.Errorf("invalid unit %q: must be D, I or M", rune(u)))
+		return b
+	}
+	if u == Dots {
+		u = 0
+	}
+	b.units = u
+	return b
+}
+
+// checkDotUnits records errUnitsMM unless the builder's units are dots.
+func (b *LabelBuilder) checkDotUnits() bool {
+	if b.units != 0 {
+		b.fail(errUnitsMM)
+		return false
+	}
+	return true
+}
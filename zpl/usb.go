@@ -0,0 +1,396 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/gousb"
+)
+
+// Default USB IDs of the Zebra TLP 2844.
+const (
+	usbVendorID  = 0x0a5f
+	usbProductID = 0x00d4
+)
+
+// usbPermissionHint is appended to permission errors on USB devices.
+const usbPermissionHint = "run as root or add a udev rule granting access to the device"
+
+// usbChunkSize is the default size of each bulk transfer.
+const usbChunkSize = 16 * 1024
+
+// USBPrinter is a printer attached through its USB bulk endpoint. It is a
+// TransportPrinter over the endpoints and is safe for concurrent use; each
+// send is written as one unit. If the device has gone away, a send
+// reopens the printer once and is retried, apart from streams.
+type USBPrinter struct {
+	*TransportPrinter
+}
+
+// NewUSBPrinter opens the first Zebra TLP 2844 found on the bus.
+func NewUSBPrinter(opts ...Option) (*USBPrinter, error) {
+	return NewUSBPrinterWithID(usbVendorID, usbProductID, opts...)
+}
+
+// NewUSBPrinterWithEndpoint opens the TLP 2844 like NewUSBPrinter but sends
+// on OUT endpoint number ep, for devices whose first OUT endpoint is not the
+// print data path.
+func NewUSBPrinterWithEndpoint(ep int, opts ...Option) (*USBPrinter, error) {
+	return NewUSBPrinter(append(opts, WithOutEndpoint(ep))...)
+}
+
+// NewUSBPrinterWithID opens the first printer matching the given vendor and
+// product IDs, for models other than the TLP 2844.
+func NewUSBPrinterWithID(vid, pid uint16, opts ...Option) (*USBPrinter, error) {
+	find := func(ctx *gousb.Context) (*gousb.Device, error) {
+		dev, err := ctx.OpenDeviceWithVIDPID(gousb.ID(vid), gousb.ID(pid))
+		if errors.Is(err, gousb.ErrorAccess) {
+			return nil, fmt.Errorf("%w: failed to open device %04x:%04x; %s: %w", ErrUSBPermission, vid, pid, usbPermissionHint, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open device %04x:%04x: %w", vid, pid, err)
+		}
+		if dev == nil {
+			return nil, fmt.Errorf("%w (%04x:%04x)", ErrPrinterNotFound, vid, pid)
+		}
+		return dev, nil
+	}
+	o := newOptions(opts).with("transport", "usb", "id", fmt.Sprintf("%04x:%04x", vid, pid))
+	return openUSBPrinter(openGousb(find), o)
+}
+
+// USBPrinterInfo describes a Zebra printer found on the USB bus.
+type USBPrinterInfo struct {
+	Serial    string
+	Product   string
+	ProductID uint16
+	Bus       int
+	Address   int
+}
+
+// ListUSBPrinters returns every attached device with the Zebra vendor ID.
+// Devices whose descriptors cannot be read are still listed, with empty
+// strings.
+func ListUSBPrinters() ([]USBPrinterInfo, error) {
+	ctx := gousb.NewContext()
+	defer ctx.Close()
+
+	devs, err := openZebraDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]USBPrinterInfo, 0, len(devs))
+	for _, dev := range devs {
+		serial, _ := dev.SerialNumber()
+		product, _ := dev.Product()
+		infos = append(infos, USBPrinterInfo{
+			Serial:    serial,
+			Product:   product,
+			ProductID: uint16(dev.Desc.Product),
+			Bus:       dev.Desc.Bus,
+			Address:   dev.Desc.Address,
+		})
+		dev.Close()
+	}
+	return infos, nil
+}
+
+// OpenUSBPrinterBySerial opens the Zebra printer with the given serial
+// number, for hosts with several printers attached.
+func OpenUSBPrinterBySerial(serial string, opts ...Option) (*USBPrinter, error) {
+	find := func(ctx *gousb.Context) (*gousb.Device, error) {
+		devs, err := openZebraDevices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var found *gousb.Device
+		for _, dev := range devs {
+			if s, err := dev.SerialNumber(); err == nil && s == serial && found == nil {
+				found = dev
+				continue
+			}
+			dev.Close()
+		}
+		if found == nil {
+			return nil, fmt.Errorf("%w (serial %q)", ErrPrinterNotFound, serial)
+		}
+		return found, nil
+	}
+	o := newOptions(opts).with("transport", "usb", "serial", serial)
+	return openUSBPrinter(openGousb(find), o)
+}
+
+// openZebraDevices opens every device with the Zebra vendor ID. Devices
+// that fail to open are skipped unless none could be opened.
+func openZebraDevices(ctx *gousb.Context) ([]*gousb.Device, error) {
+	devs, err := ctx.OpenDevices(func(desc *gousb.DeviceDesc) bool {
+		return desc.Vendor == usbVendorID
+	})
+	if err != nil && len(devs) == 0 {
+		if errors.Is(err, gousb.ErrorAccess) {
+			return nil, fmt.Errorf("%w: failed to open Zebra devices; %s: %w", ErrUSBPermission, usbPermissionHint, err)
+		}
+		return nil, fmt.Errorf("failed to enumerate USB devices: %w", err)
+	}
+	return devs, nil
+}
+
+// openUSBPrinter opens the printer with open, then claims its interface
+// and opens the endpoints.
+func openUSBPrinter(open func() (usbDevice, error), opts options) (*USBPrinter, error) {
+	t, err := claimUSBPrinter(open, opts)
+	if err != nil {
+		return nil, err
+	}
+	size := opts.usbChunkSize
+	if size <= 0 {
+		size = usbChunkSize
+	}
+	p := &USBPrinter{TransportPrinter: newTransportPrinter(t, opts, size)}
+	p.redial = &redialer{
+		open: func() (Transport, error) {
+			t, err := claimUSBPrinter(open, opts)
+			if err != nil {
+				return nil, err
+			}
+			return t, nil
+		},
+		retries:   1,
+		retryable: isDeviceGone,
+		raw:       true,
+	}
+	if err := opts.connected(p); err != nil {
+		p.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// claimUSBPrinter opens the device with open and claims its endpoints. On
+// failure everything it opened is closed again.
+func claimUSBPrinter(open func() (usbDevice, error), opts options) (_ *usbTransport, err error) {
+	dev, err := open()
+	if err != nil {
+		return nil, err
+	}
+	var intf usbInterface
+	defer func() {
+		if err != nil {
+			if intf != nil {
+				intf.release()
+			}
+			dev.close()
+		}
+	}()
+
+	// Let libusb detach the kernel driver for us. Platforms without kernel
+	// drivers to detach report that it is not supported.
+	if !opts.noAutoDetach {
+		if err := dev.setAutoDetach(true); err != nil && !errors.Is(err, gousb.ErrorNotSupported) {
+			if errors.Is(err, gousb.ErrorAccess) {
+				return nil, fmt.Errorf("%w: failed to enable kernel driver auto-detach; %s: %w", ErrUSBPermission, usbPermissionHint, err)
+			}
+			return nil, fmt.Errorf("failed to enable kernel driver auto-detach; use WithAutoDetach(false) if the driver is managed elsewhere: %w", err)
+		}
+	}
+
+	// Claim the requested interface, by default interface 0 of the active
+	// configuration
+	if intf, err = dev.claim(opts.usbConfig, opts.usbInterface, opts.usbAltSetting); err != nil {
+		switch {
+		case errors.Is(err, gousb.ErrorAccess):
+			return nil, fmt.Errorf("%w: %w; %s: %w", ErrUSBPermission, ErrInterfaceNotClaimed, usbPermissionHint, err)
+		case errors.Is(err, gousb.ErrorBusy):
+			return nil, fmt.Errorf("%w: the kernel printer driver or another process holds it; enable auto-detach or unbind usblp: %w", ErrInterfaceNotClaimed, err)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInterfaceNotClaimed, err)
+	}
+	if len(intf.setting().Endpoints) == 0 {
+		return nil, fmt.Errorf("%w: %w: interface %d has none; composite devices may expose the printer on another interface, see WithUSBConfig and WithUSBInterface",
+			ErrNoEndpoints, ErrNoOutEndpoint, opts.usbInterface)
+	}
+
+	// Find the OUT endpoint, either the requested or the lowest numbered one
+	outNum, err := outEndpointNumber(intf.setting(), opts.outEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	outEP, err := intf.outEndpoint(outNum)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNoOutEndpoint, err)
+	}
+
+	// The IN endpoint is optional; without it the printer is write-only
+	var inEP usbReader
+	if inNum, ok := lowestEndpoint(intf.setting(), gousb.EndpointDirectionIn); ok {
+		if inEP, err = intf.inEndpoint(inNum); err != nil {
+			return nil, fmt.Errorf("failed to open IN endpoint: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &usbTransport{
+		dev:          dev,
+		intf:         intf,
+		outEP:        outEP,
+		inEP:         inEP,
+		writeTimeout: opts.usbWriteTimeout,
+		ctx:          ctx,
+		cancel:       cancel,
+	}, nil
+}
+
+// Reopen releases the current device handle and finds the printer again by
+// the IDs or serial number it was opened with, so a printer that was
+// unplugged and plugged back in can be used without a restart. On failure
+// the printer stays closed and Reopen can be called again. A printer shut
+// with Close stays shut: Reopen then fails with ErrNotConnected.
+func (p *USBPrinter) Reopen() error {
+	p.mu.Lock()
+	err := p.replace()
+	p.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	p.opts.log().Info("printer reopened")
+	return p.opts.connected(p)
+}
+
+// outEndpointNumber returns want if it is an OUT endpoint of setting, or
+// the lowest numbered OUT endpoint when want is 0.
+func outEndpointNumber(setting gousb.InterfaceSetting, want int) (int, error) {
+	if want == 0 {
+		num, ok := lowestEndpoint(setting, gousb.EndpointDirectionOut)
+		if !ok {
+			return 0, fmt.Errorf("%w: composite devices may expose the printer on another interface, see WithUSBConfig and WithUSBInterface", ErrNoOutEndpoint)
+		}
+		return num, nil
+	}
+	for _, ep := range setting.Endpoints {
+		if ep.Number != want {
+			continue
+		}
+		if ep.Direction == gousb.EndpointDirectionOut {
+			return want, nil
+		}
+		return 0, fmt.Errorf("%w: endpoint %d is an IN endpoint", ErrNoOutEndpoint, want)
+	}
+	return 0, fmt.Errorf("%w: interface has no endpoint %d", ErrNoOutEndpoint, want)
+}
+
+// lowestEndpoint picks the lowest numbered endpoint in direction dir, so
+// the choice does not depend on map iteration order.
+func lowestEndpoint(setting gousb.InterfaceSetting, dir gousb.EndpointDirection) (int, bool) {
+	num, found := 0, false
+	for _, ep := range setting.Endpoints {
+		if ep.Direction == dir && (!found || ep.Number < num) {
+			num, found = ep.Number, true
+		}
+	}
+	return num, found
+}
+
+// usbTransport is the claimed interface of a USBPrinter.
+type usbTransport struct {
+	dev          usbDevice
+	intf         usbInterface
+	outEP        usbWriter
+	inEP         usbReader     // nil for write-only printers
+	writeTimeout time.Duration // bounds each bulk transfer, unless zero
+
+	// ctx is cancelled by Close, aborting a read in progress
+	ctx    context.Context
+	cancel context.CancelFunc
+	// reading is held by reads in progress, so that Close does not release
+	// the interface under their transfer
+	reading sync.RWMutex
+
+	mu       sync.Mutex
+	deadline time.Time // of reads, zero for none
+}
+
+// Write writes b in one bulk transfer.
+func (t *usbTransport) Write(b []byte) (int, error) {
+	return t.WriteContext(context.Background(), b)
+}
+
+// WriteContext writes b in one bulk transfer, cancelled when ctx is done
+// and bounded by the configured write timeout.
+func (t *usbTransport) WriteContext(ctx context.Context, b []byte) (int, error) {
+	wctx, cancel := ctx, context.CancelFunc(func() {})
+	if t.writeTimeout > 0 {
+		wctx, cancel = context.WithTimeout(ctx, t.writeTimeout)
+	}
+	defer cancel()
+	n, err := t.outEP.WriteContext(wctx, b)
+	if err != nil && ctx.Err() == nil && wctx.Err() != nil {
+		return n, fmt.Errorf("write timed out after %s: %w", t.writeTimeout, err)
+	}
+	return n, err
+}
+
+// SetReadDeadline makes reads give up with os.ErrDeadlineExceeded at t.
+func (t *usbTransport) SetReadDeadline(deadline time.Time) error {
+	if t.inEP == nil {
+		return ErrNoInEndpoint
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.deadline = deadline
+	return nil
+}
+
+// Read reads a response from the bulk IN endpoint. For best results len(b)
+// should be a multiple of the endpoint's max packet size.
+func (t *usbTransport) Read(b []byte) (int, error) {
+	if t.inEP == nil {
+		return 0, ErrNoInEndpoint
+	}
+	t.reading.RLock()
+	defer t.reading.RUnlock()
+	if t.ctx.Err() != nil {
+		return 0, fmt.Errorf("%w: usb device is closed", ErrNotConnected)
+	}
+	t.mu.Lock()
+	deadline := t.deadline
+	t.mu.Unlock()
+	ctx := t.ctx
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	n, err := t.inEP.ReadContext(ctx, b)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return n, os.ErrDeadlineExceeded
+	}
+	return n, err
+}
+
+// Ping checks that the device is still attached by issuing a control
+// request on it.
+func (t *usbTransport) Ping() error {
+	if _, err := t.dev.activeConfigNum(); err != nil {
+		return fmt.Errorf("%w: %w", ErrNotConnected, err)
+	}
+	return nil
+}
+
+// Close aborts a read in progress, then releases the interface and closes
+// the device.
+func (t *usbTransport) Close() error {
+	t.cancel()
+	t.reading.Lock()
+	defer t.reading.Unlock()
+	t.intf.release()
+	return t.dev.close()
+}
+
+// isDeviceGone reports whether err means the device was disconnected.
+func isDeviceGone(err error) bool {
+	return errors.Is(err, gousb.ErrorNoDevice) || errors.Is(err, gousb.TransferNoDevice)
+}
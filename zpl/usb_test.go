@@ -0,0 +1,427 @@
+package zpl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/gousb"
+)
+
+// fakeUSB is a usbDevice, and its interface and endpoints, that records
+// writes instead of talking to hardware.
+type fakeUSB struct {
+	mu        sync.Mutex
+	endpoints []gousb.EndpointDesc
+	written   []string // one entry per bulk transfer
+	outNum    int      // OUT endpoint opened
+	response  string   // returned by the next read
+
+	detachErr error
+	claimErr  error
+	writeErrs []error // returned by successive writes, then nil
+	short     int     // bytes withheld from every write
+	maxWrite  int     // cap on the bytes taken by one write, 0 for none
+
+	claimed          [3]int // configuration, interface and alternate setting
+	released, closed bool
+}
+
+func newFakeUSB(endpoints ...gousb.EndpointDesc) *fakeUSB {
+	return &fakeUSB{endpoints: endpoints}
+}
+
+func outEP(num int) gousb.EndpointDesc {
+	return gousb.EndpointDesc{Address: gousb.EndpointAddress(num), Number: num, Direction: gousb.EndpointDirectionOut}
+}
+
+func inEP(num int) gousb.EndpointDesc {
+	return gousb.EndpointDesc{Address: gousb.EndpointAddress(0x80 | num), Number: num, Direction: gousb.EndpointDirectionIn}
+}
+
+func (f *fakeUSB) setAutoDetach(bool) error      { return f.detachErr }
+func (f *fakeUSB) activeConfigNum() (int, error) { return 1, nil }
+
+func (f *fakeUSB) claim(cfg, intf, alt int) (usbInterface, error) {
+	if f.claimErr != nil {
+		return nil, f.claimErr
+	}
+	f.claimed = [3]int{cfg, intf, alt}
+	return f, nil
+}
+
+func (f *fakeUSB) close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeUSB) setting() gousb.InterfaceSetting {
+	s := gousb.InterfaceSetting{Endpoints: make(map[gousb.EndpointAddress]gousb.EndpointDesc)}
+	for _, ep := range f.endpoints {
+		s.Endpoints[ep.Address] = ep
+	}
+	return s
+}
+
+func (f *fakeUSB) outEndpoint(num int) (usbWriter, error) {
+	f.outNum = num
+	return f, nil
+}
+
+func (f *fakeUSB) inEndpoint(int) (usbReader, error) { return f, nil }
+func (f *fakeUSB) release()                          { f.released = true }
+
+func (f *fakeUSB) WriteContext(ctx context.Context, b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.writeErrs) > 0 {
+		err := f.writeErrs[0]
+		f.writeErrs = f.writeErrs[1:]
+		if err != nil {
+			return 0, err
+		}
+	}
+	n := max(len(b)-f.short, 0)
+	if f.maxWrite > 0 {
+		n = min(n, f.maxWrite)
+	}
+	f.written = append(f.written, string(b[:n]))
+	return n, nil
+}
+
+func (f *fakeUSB) ReadContext(ctx context.Context, b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.response == "" {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	n := copy(b, f.response)
+	f.response = f.response[n:]
+	return n, nil
+}
+
+func (f *fakeUSB) sent() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return strings.Join(f.written, "")
+}
+
+// openFake opens a USBPrinter on dev.
+func openFake(t *testing.T, dev *fakeUSB, opts ...Option) *USBPrinter {
+	t.Helper()
+	p, err := openUSBPrinter(func() (usbDevice, error) { return dev, nil }, newOptions(opts))
+	if err != nil {
+		t.Fatalf("openUSBPrinter: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestUSBSendZPLAddsNewline(t *testing.T) {
+	tests := []struct {
+		zpl  string
+		want string
+	}{
+		{"^XA^XZ", "^XA^XZ\n"},
+		{"^XA^XZ\n", "^XA^XZ\n"},
+		{"", "\n"},
+	}
+	for _, tt := range tests {
+		dev := newFakeUSB(outEP(1))
+		p := openFake(t, dev)
+		if err := p.SendZPL(tt.zpl); err != nil {
+			t.Fatalf("SendZPL(%q): %v", tt.zpl, err)
+		}
+		if got := dev.sent(); got != tt.want {
+			t.Errorf("SendZPL(%q) wrote %q, want %q", tt.zpl, got, tt.want)
+		}
+	}
+}
+
+func TestUSBRawSendUnchanged(t *testing.T) {
+	dev := newFakeUSB(outEP(1))
+	p := openFake(t, dev)
+	if err := p.RawSend([]byte("~DYR:X,B,T,1,,\x00")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := dev.sent(), "~DYR:X,B,T,1,,\x00"; got != want {
+		t.Errorf("RawSend wrote %q, want %q", got, want)
+	}
+}
+
+func TestUSBOpenErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		dev  *fakeUSB
+		open error
+		want error
+	}{
+		{"not found", nil, ErrPrinterNotFound, ErrPrinterNotFound},
+		{"no OUT endpoint", newFakeUSB(inEP(2)), nil, ErrNoOutEndpoint},
+		{"no endpoints", newFakeUSB(), nil, ErrNoEndpoints},
+		{"no endpoints is no OUT endpoint", newFakeUSB(), nil, ErrNoOutEndpoint},
+		{"claim busy", &fakeUSB{endpoints: []gousb.EndpointDesc{outEP(1)}, claimErr: gousb.ErrorBusy}, nil, ErrInterfaceNotClaimed},
+		{"claim failed", &fakeUSB{endpoints: []gousb.EndpointDesc{outEP(1)}, claimErr: gousb.ErrorIO}, nil, ErrInterfaceNotClaimed},
+		{"claim denied", &fakeUSB{endpoints: []gousb.EndpointDesc{outEP(1)}, claimErr: gousb.ErrorAccess}, nil, ErrUSBPermission},
+		{"detach denied", &fakeUSB{endpoints: []gousb.EndpointDesc{outEP(1)}, detachErr: gousb.ErrorAccess}, nil, ErrUSBPermission},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := openUSBPrinter(func() (usbDevice, error) {
+				if tt.open != nil {
+					return nil, tt.open
+				}
+				return tt.dev, nil
+			}, newOptions(nil))
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got error %v, want %v", err, tt.want)
+			}
+			if tt.dev != nil && !tt.dev.closed {
+				t.Error("device left open after failure")
+			}
+			if tt.dev != nil && tt.dev.claimErr == nil && tt.dev.detachErr == nil && !tt.dev.released {
+				t.Error("interface left claimed after failure")
+			}
+		})
+	}
+}
+
+func TestUSBDetachNotSupported(t *testing.T) {
+	dev := &fakeUSB{endpoints: []gousb.EndpointDesc{outEP(1)}, detachErr: gousb.ErrorNotSupported}
+	openFake(t, dev)
+}
+
+func TestUSBInterfaceSelection(t *testing.T) {
+	dev := newFakeUSB(outEP(1))
+	openFake(t, dev)
+	if dev.claimed != [3]int{0, 0, 0} {
+		t.Errorf("claimed %v by default, want the active configuration's interface 0", dev.claimed)
+	}
+	dev = newFakeUSB(outEP(1))
+	openFake(t, dev, WithUSBConfig(2), WithUSBInterface(1, 3))
+	if dev.claimed != [3]int{2, 1, 3} {
+		t.Errorf("claimed %v, want configuration 2, interface 1, setting 3", dev.claimed)
+	}
+}
+
+func TestCheckUSBSetting(t *testing.T) {
+	desc := &gousb.DeviceDesc{Configs: map[int]gousb.ConfigDesc{
+		1: {Number: 1, Interfaces: []gousb.InterfaceDesc{
+			{Number: 0, AltSettings: []gousb.InterfaceSetting{{Number: 0, Alternate: 0}}},
+			{Number: 1, AltSettings: []gousb.InterfaceSetting{{Number: 1, Alternate: 0}, {Number: 1, Alternate: 1}}},
+		}},
+	}}
+	tests := []struct {
+		cfg, intf, alt int
+		ok             bool
+	}{
+		{1, 0, 0, true},
+		{1, 1, 1, true},
+		{2, 0, 0, false},
+		{1, 2, 0, false},
+		{1, 0, 1, false},
+	}
+	for _, tt := range tests {
+		err := checkUSBSetting(desc, tt.cfg, tt.intf, tt.alt)
+		if (err == nil) != tt.ok {
+			t.Errorf("checkUSBSetting(%d, %d, %d) = %v, want ok %v", tt.cfg, tt.intf, tt.alt, err, tt.ok)
+		}
+	}
+}
+
+func TestUSBEndpointSelection(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []gousb.EndpointDesc
+		want      int // requested endpoint, 0 for the default
+		got       int
+		err       error
+	}{
+		{"only endpoint", []gousb.EndpointDesc{outEP(1), inEP(2)}, 0, 1, nil},
+		{"lowest of several", []gousb.EndpointDesc{outEP(5), outEP(3), outEP(4), inEP(1)}, 0, 3, nil},
+		{"requested", []gousb.EndpointDesc{outEP(1), outEP(3)}, 3, 3, nil},
+		{"requested IN", []gousb.EndpointDesc{outEP(1), inEP(2)}, 2, 0, ErrNoOutEndpoint},
+		{"requested missing", []gousb.EndpointDesc{outEP(1)}, 7, 0, ErrNoOutEndpoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := newFakeUSB(tt.endpoints...)
+			// Map iteration order varies, so repeat to catch order bugs
+			for range 20 {
+				p, err := openUSBPrinter(func() (usbDevice, error) { return dev, nil }, newOptions([]Option{WithOutEndpoint(tt.want)}))
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("got error %v, want %v", err, tt.err)
+				}
+				if err != nil {
+					return
+				}
+				p.Close()
+				if dev.outNum != tt.got {
+					t.Fatalf("opened OUT endpoint %d, want %d", dev.outNum, tt.got)
+				}
+			}
+		})
+	}
+}
+
+func TestUSBChunkedWrites(t *testing.T) {
+	dev := newFakeUSB(outEP(1))
+	p := openFake(t, dev, WithUSBChunkSize(4))
+	n, err := p.SendZPLN("^XA^FDabc^FS^XZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 16 {
+		t.Errorf("SendZPLN returned %d bytes, want 16", n)
+	}
+	if len(dev.written) != 4 {
+		t.Errorf("got %d transfers, want 4: %q", len(dev.written), dev.written)
+	}
+}
+
+func TestUSBPartialWrites(t *testing.T) {
+	const zpl = "^XA^FO10,10^A0N,30,30^FDpartial^FS^XZ"
+	dev := newFakeUSB(outEP(1))
+	dev.maxWrite = 5
+	p := openFake(t, dev, WithUSBChunkSize(16))
+	n, err := p.SendZPLN(zpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(zpl)+1 {
+		t.Errorf("SendZPLN returned %d bytes, want %d", n, len(zpl)+1)
+	}
+	if got := dev.sent(); got != zpl+"\n" {
+		t.Errorf("wrote %q, want %q", got, zpl+"\n")
+	}
+	for i, w := range dev.written {
+		if len(w) > 5 {
+			t.Errorf("transfer %d took %d bytes, want at most 5", i, len(w))
+		}
+	}
+}
+
+func TestUSBPartialWritesReader(t *testing.T) {
+	dev := newFakeUSB(outEP(1))
+	dev.maxWrite = 3
+	p := openFake(t, dev)
+	if err := p.SendZPLReader(strings.NewReader("^XA^FDstream^FS^XZ")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := dev.sent(), "^XA^FDstream^FS^XZ\n"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+}
+
+func TestUSBShortWrite(t *testing.T) {
+	dev := newFakeUSB(outEP(1))
+	dev.short = 1
+	p := openFake(t, dev)
+	n, err := p.SendZPLN("^XA^XZ")
+	if !errors.Is(err, io.ErrShortWrite) || !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("got error %v, want io.ErrShortWrite and ErrNotConnected", err)
+	}
+	// The first transfer takes all but one byte; the retry for that byte
+	// makes no progress
+	if n != 6 {
+		t.Errorf("SendZPLN returned %d bytes, want 6", n)
+	}
+}
+
+func TestUSBReopensWhenDeviceGone(t *testing.T) {
+	first := newFakeUSB(outEP(1))
+	first.writeErrs = []error{gousb.ErrorNoDevice}
+	second := newFakeUSB(outEP(1))
+	devs := []*fakeUSB{first, second}
+	p, err := openUSBPrinter(func() (usbDevice, error) {
+		if len(devs) == 0 {
+			return nil, ErrPrinterNotFound
+		}
+		d := devs[0]
+		devs = devs[1:]
+		return d, nil
+	}, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	if err := p.SendZPL("^XA^XZ"); err != nil {
+		t.Fatalf("SendZPL: %v", err)
+	}
+	if !first.closed {
+		t.Error("old device not closed on reopen")
+	}
+	if got := second.sent(); got != "^XA^XZ\n" {
+		t.Errorf("reopened device got %q", got)
+	}
+}
+
+func TestUSBClosed(t *testing.T) {
+	dev := newFakeUSB(outEP(1))
+	p := openFake(t, dev)
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !dev.released || !dev.closed {
+		t.Error("Close did not release the interface and close the device")
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if err := p.SendZPL("^XA^XZ"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("SendZPL after Close: got %v, want ErrNotConnected", err)
+	}
+}
+
+func TestUSBStatus(t *testing.T) {
+	dev := newFakeUSB(outEP(1), inEP(2))
+	p := openFake(t, dev)
+	dev.response = "\x02030,0,0,1245,000,0,0,0,000,0,0,0\x03\r\n" +
+		"\x02001,0,0,0,1,2,6,0,00000000,1,000\x03\r\n" +
+		"\x021234,0\x03\r\n"
+	s, err := p.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.LabelLength != 1245 {
+		t.Errorf("label length %d, want 1245", s.LabelLength)
+	}
+	if got := dev.sent(); got != "~HS\n" {
+		t.Errorf("sent %q, want ~HS", got)
+	}
+}
+
+func TestUSBWriteOnly(t *testing.T) {
+	p := openFake(t, newFakeUSB(outEP(1)))
+	if _, err := p.Read(make([]byte, 8)); !errors.Is(err, ErrNoInEndpoint) {
+		t.Errorf("Read: got %v, want ErrNoInEndpoint", err)
+	}
+}
+
+func TestUSBReopenAfterClose(t *testing.T) {
+	opened := 0
+	p, err := openUSBPrinter(func() (usbDevice, error) {
+		opened++
+		return newFakeUSB(outEP(1)), nil
+	}, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Reopen(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Reopen after Close: got %v, want ErrNotConnected", err)
+	}
+	if opened != 1 {
+		t.Errorf("device opened %d times, want once", opened)
+	}
+	if err := p.SendZPL("^XA^XZ"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("SendZPL after Reopen: got %v, want ErrNotConnected", err)
+	}
+}
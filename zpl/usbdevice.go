@@ -0,0 +1,151 @@
+package zpl
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/gousb"
+)
+
+// usbDevice is the part of an open USB device that USBPrinter uses. It is
+// implemented over gousb by gousbDevice and by a fake in the tests.
+type usbDevice interface {
+	// setAutoDetach lets libusb detach the kernel driver when claiming.
+	setAutoDetach(enabled bool) error
+	// claim claims interface intf with alternate setting alt of
+	// configuration cfg, or of the active configuration if cfg is 0.
+	claim(cfg, intf, alt int) (usbInterface, error)
+	// activeConfigNum issues a control request, failing if the device is
+	// gone.
+	activeConfigNum() (int, error)
+	// close closes the device and everything opened to reach it.
+	close() error
+}
+
+// usbInterface is a claimed USB interface.
+type usbInterface interface {
+	setting() gousb.InterfaceSetting
+	outEndpoint(num int) (usbWriter, error)
+	inEndpoint(num int) (usbReader, error)
+	// release releases the interface and its configuration.
+	release()
+}
+
+// usbWriter is a bulk OUT endpoint.
+type usbWriter interface {
+	WriteContext(ctx context.Context, b []byte) (int, error)
+}
+
+// usbReader is a bulk IN endpoint.
+type usbReader interface {
+	ReadContext(ctx context.Context, b []byte) (int, error)
+}
+
+// gousbDevice is a usbDevice along with the gousb context it was opened in.
+type gousbDevice struct {
+	ctx *gousb.Context
+	dev *gousb.Device
+}
+
+// openGousb returns an opener that finds the device with find in a new
+// gousb context.
+func openGousb(find func(*gousb.Context) (*gousb.Device, error)) func() (usbDevice, error) {
+	return func() (usbDevice, error) {
+		ctx := gousb.NewContext()
+		dev, err := find(ctx)
+		if err != nil {
+			ctx.Close()
+			return nil, err
+		}
+		return &gousbDevice{ctx: ctx, dev: dev}, nil
+	}
+}
+
+func (d *gousbDevice) setAutoDetach(enabled bool) error {
+	return d.dev.SetAutoDetach(enabled)
+}
+
+func (d *gousbDevice) claim(cfgNum, num, alt int) (usbInterface, error) {
+	if cfgNum == 0 {
+		var err error
+		if cfgNum, err = d.dev.ActiveConfigNum(); err != nil {
+			return nil, fmt.Errorf("failed to get active configuration: %w", err)
+		}
+	}
+	if err := checkUSBSetting(d.dev.Desc, cfgNum, num, alt); err != nil {
+		return nil, err
+	}
+	cfg, err := d.dev.Config(cfgNum)
+	if err != nil {
+		return nil, err
+	}
+	intf, err := cfg.Interface(num, alt)
+	if err != nil {
+		cfg.Close()
+		return nil, err
+	}
+	return &gousbInterface{intf: intf, done: func() {
+		intf.Close()
+		cfg.Close()
+	}}, nil
+}
+
+// checkUSBSetting reports whether desc has configuration cfg with
+// interface num and alternate setting alt, listing the ones it does have
+// if not.
+func checkUSBSetting(desc *gousb.DeviceDesc, cfg, num, alt int) error {
+	c, ok := desc.Configs[cfg]
+	if !ok {
+		nums := slices.Sorted(maps.Keys(desc.Configs))
+		return fmt.Errorf("device has no configuration %d, only %v", cfg, nums)
+	}
+	var intfs []string
+	for _, intf := range c.Interfaces {
+		var alts []int
+		for _, s := range intf.AltSettings {
+			if intf.Number == num && s.Alternate == alt {
+				return nil
+			}
+			alts = append(alts, s.Alternate)
+		}
+		intfs = append(intfs, fmt.Sprintf("%d (alternate settings %v)", intf.Number, alts))
+	}
+	return fmt.Errorf("configuration %d has no interface %d with alternate setting %d, only %s", cfg, num, alt, strings.Join(intfs, ", "))
+}
+
+func (d *gousbDevice) activeConfigNum() (int, error) {
+	return d.dev.ActiveConfigNum()
+}
+
+func (d *gousbDevice) close() error {
+	err := d.dev.Close()
+	if cerr := d.ctx.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// gousbInterface is a usbInterface claimed with gousb.
+type gousbInterface struct {
+	intf *gousb.Interface
+	done func()
+}
+
+func (i *gousbInterface) setting() gousb.InterfaceSetting {
+	return i.intf.Setting
+}
+
+func (i *gousbInterface) outEndpoint(num int) (usbWriter, error) {
+	return i.intf.OutEndpoint(num)
+}
+
+func (i *gousbInterface) inEndpoint(num int) (usbReader, error) {
+	return i.intf.InEndpoint(num)
+}
+
+func (i *gousbInterface) release() {
+	i.done()
+}
@@ -0,0 +1,101 @@
+package zpl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a structural problem found by ValidateZPL.
+type ValidationError struct {
+	Offset int    // byte offset of the offending command
+	Reason string // what is wrong
+	Err    error  // ErrIncompleteFormat for a format missing ^XA or ^XZ, else nil
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid ZPL at offset %d: %s", e.Offset, e.Reason)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateZPL performs a structural check of zpl: every ^XA must be closed
+// by ^XZ before the next ^XA, every ^FD or ^FV field must end with ^FS, and
+// ^ format commands must appear inside a format. Tilde control commands are
+// allowed anywhere. It does not check command parameters. Prefix changes
+// made with ^CC or ~CC and ^CT or ~CT are followed as in ParseZPL, and
+// errors name commands by their default prefix.
+//
+// A fragment missing its ^XA or ^XZ, which the printer would hold in its
+// buffer waiting for the rest, fails with an error wrapping
+// ErrIncompleteFormat.
+func ValidateZPL(zpl string) error {
+	format, control := byte('^'), byte('~')
+	formatStart, dataStart := -1, -1
+	for i := 0; i < len(zpl); i++ {
+		c := zpl[i]
+		if c != format && c != control {
+			continue
+		}
+		// Control prefixes inside field data are literal
+		if c != format && dataStart >= 0 {
+			continue
+		}
+		code := strings.ToUpper(zpl[i+1 : min(i+3, len(zpl))])
+
+		if dataStart >= 0 {
+			if code == "FS" {
+				dataStart = -1
+				continue
+			}
+			return &ValidationError{dataStart, "^FD field is not terminated by ^FS", nil}
+		}
+		if code == "CC" || code == "CT" {
+			if c == format && formatStart < 0 {
+				return &ValidationError{i, fmt.Sprintf("^%s outside of a ^XA/^XZ format", code), ErrIncompleteFormat}
+			}
+			// The new prefix is the one character after the code
+			if i+3 < len(zpl) {
+				if code == "CC" {
+					format = zpl[i+3]
+				} else {
+					control = zpl[i+3]
+				}
+				i += 3
+			}
+			continue
+		}
+		if c != format {
+			continue
+		}
+
+		switch code {
+		case "XA":
+			if formatStart >= 0 {
+				return &ValidationError{i, fmt.Sprintf("^XA inside the format started at offset %d", formatStart), ErrIncompleteFormat}
+			}
+			formatStart = i
+		case "XZ":
+			if formatStart < 0 {
+				return &ValidationError{i, "^XZ without a matching ^XA", ErrIncompleteFormat}
+			}
+			formatStart = -1
+		default:
+			if formatStart < 0 {
+				return &ValidationError{i, fmt.Sprintf("^%s outside of a ^XA/^XZ format", code), ErrIncompleteFormat}
+			}
+			if code == "FD" || code == "FV" {
+				dataStart = i
+			}
+		}
+	}
+
+	if dataStart >= 0 {
+		return &ValidationError{dataStart, "^FD field is not terminated by ^FS", nil}
+	}
+	if formatStart >= 0 {
+		return &ValidationError{formatStart, "^XA without a matching ^XZ", ErrIncompleteFormat}
+	}
+	return nil
+}
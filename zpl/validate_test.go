@@ -0,0 +1,66 @@
+package zpl
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateZPL(t *testing.T) {
+	tests := []struct {
+		name, zpl string
+	}{
+		{"empty", ""},
+		{"one format", "^XA^FO10,10^A0N,30,30^FDHello^FS^XZ"},
+		{"two formats", "^XA^FDa^FS^XZ\n^XA^FDb^FS^XZ"},
+		{"lower case", "^xa^fo10,10^fdHello^fs^xz"},
+		{"control commands outside", "~JA^XA^FDa^FS^XZ~HS"},
+		{"prefixes in field data", "^XA^FD~HS and ~CC^FS^XZ"},
+		{"format prefix changed", "^XA^CC!!FO10,10!FDa^b!FS!XZ"},
+		{"control prefix changed", "~CT+^XA^FDa+HS^FS^XZ+HS"},
+		{"both prefixes changed", "^XA^CC!!CT+!XZ+HS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateZPL(tt.zpl); err != nil {
+				t.Errorf("ValidateZPL(%q) = %v", tt.zpl, err)
+			}
+		})
+	}
+}
+
+func TestValidateZPLInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		zpl        string
+		offset     int
+		incomplete bool
+	}{
+		{"missing ^XZ", "^XA^FDa^FS", 0, true},
+		{"missing ^XA", "^FDa^FS^XZ", 0, true},
+		{"stray ^XZ", "^XA^XZ^XZ", 6, true},
+		{"nested ^XA", "^XA^FO0,0^XA^XZ", 9, true},
+		{"lower case nested ^xa", "^xa^xa^xz", 3, true},
+		{"command outside a format", "^XA^XZ^FO0,0", 6, true},
+		{"missing ^FS", "^XA^FDa^FO0,0^FDb^FS^XZ", 3, false},
+		{"missing ^FS at the end", "^XA^FDa", 3, false},
+		{"lower case missing ^fs", "^xa^fva^xz", 3, false},
+		{"^CC outside a format", "^CC!!XA!XZ", 0, true},
+		{"old prefix after ^CC", "^XA^CC!^XZ", 0, true},
+		{"missing ^FS after ^CC", "^XA^CC!!FDa!XZ", 7, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateZPL(tt.zpl)
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("ValidateZPL(%q) = %v, want a *ValidationError", tt.zpl, err)
+			}
+			if verr.Offset != tt.offset {
+				t.Errorf("ValidateZPL(%q) offset = %d, want %d", tt.zpl, verr.Offset, tt.offset)
+			}
+			if got := errors.Is(err, ErrIncompleteFormat); got != tt.incomplete {
+				t.Errorf("errors.Is(%v, ErrIncompleteFormat) = %v, want %v", err, got, tt.incomplete)
+			}
+		})
+	}
+}
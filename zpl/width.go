@@ -0,0 +1,36 @@
+package zpl
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DefaultPrintWidth is the print width AutoWidth falls back to: the full
+// width of a 4 inch printhead at 203 dpi.
+const DefaultPrintWidth = 812
+
+// AutoWidth reads the printer's configured print width in dots from the
+// ezpl.print_width setting, to pass to LabelBuilder.Width ahead of a
+// template that may lack ^PW. If the printer cannot be queried it returns
+// DefaultPrintWidth along with the error, so callers may log the error as a
+// warning and carry on with the fallback.
+func AutoWidth(p PrinterConnection) (int, error) {
+	width, err := printWidth(p)
+	if err != nil {
+		return DefaultPrintWidth, fmt.Errorf("using default print width %d: %w", DefaultPrintWidth, err)
+	}
+	return width, nil
+}
+
+// printWidth reads ezpl.print_width from p.
+func printWidth(p PrinterConnection) (int, error) {
+	v, err := GetVar(p, "ezpl.print_width")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read print width: %w", err)
+	}
+	width, err := strconv.Atoi(v)
+	if err != nil || width <= 0 {
+		return 0, fmt.Errorf("%w: print width %q", ErrMalformedResponse, v)
+	}
+	return width, nil
+}
@@ -0,0 +1,63 @@
+package zpl
+
+import "errors"
+
+// PrinterWriter adapts a PrinterConnection to io.WriteCloser for code that
+// produces ZPL into an io.Writer. Writes are buffered and sent with SendZPL
+// one complete batch of formats at a time, whenever a ^XZ arrives. A
+// PrinterWriter is not safe for concurrent use.
+type PrinterWriter struct {
+	p       PrinterConnection
+	buf     []byte
+	scanned int // bytes of buf already searched for ^XZ
+}
+
+// AsWriter returns a PrinterWriter that sends to p.
+func AsWriter(p PrinterConnection) *PrinterWriter {
+	return &PrinterWriter{p: p}
+}
+
+// Write buffers b and sends everything up to the last ^XZ in the buffer. If
+// the send fails, those formats are discarded and the error is returned.
+func (w *PrinterWriter) Write(b []byte) (int, error) {
+	w.buf = append(w.buf, b...)
+	end := lastFormatEnd(w.buf[max(w.scanned-2, 0):])
+	if end < 0 {
+		w.scanned = len(w.buf)
+		return len(b), nil
+	}
+	end += max(w.scanned-2, 0)
+
+	data := string(w.buf[:end])
+	w.buf = append(w.buf[:0], w.buf[end:]...)
+	w.scanned = 0
+	if err := w.p.SendZPL(data); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// Flush sends whatever is buffered, even without a closing ^XZ.
+func (w *PrinterWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	data := string(w.buf)
+	w.buf = w.buf[:0]
+	w.scanned = 0
+	return w.p.SendZPL(data)
+}
+
+// Close flushes the buffer and closes the printer connection.
+func (w *PrinterWriter) Close() error {
+	return errors.Join(w.Flush(), w.p.Close())
+}
+
+// lastFormatEnd returns the offset just past the last ^XZ in b, or -1.
+func lastFormatEnd(b []byte) int {
+	i := lastIndexFold(b, "^XZ")
+	if i < 0 {
+		return -1
+	}
+	return i + 3
+}
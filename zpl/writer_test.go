@@ -0,0 +1,52 @@
+package zpl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrinterWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		sent   []string
+		left   string // still buffered after the writes
+	}{
+		{"one format", []string{"^XA^FDa^FS^XZ"}, []string{"^XA^FDa^FS^XZ"}, ""},
+		{"lower case", []string{"^xa^fda^fs^xz\n"}, []string{"^xa^fda^fs^xz"}, "\n"},
+		{"split across writes", []string{"^XA^FD", "a^FS^", "X", "Z^XA"}, []string{"^XA^FDa^FS^XZ"}, "^XA"},
+		{"several formats in one write", []string{"^XA^XZ^XA^XZ^XA"}, []string{"^XA^XZ^XA^XZ"}, "^XA"},
+		{"code page 1252 data", []string{"^XA^FDcaf\xe9 ok^FS^XZ^X"}, []string{"^XA^FDcaf\xe9 ok^FS^XZ"}, "^X"},
+		{"long s", []string{"^XA^FDſſſ^FS", "^XZ"}, []string{"^XA^FDſſſ^FS^XZ"}, ""},
+		{"no end", []string{"^XA^FDa", "^FS"}, nil, "^XA^FDa^FS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMockPrinter()
+			w := AsWriter(m)
+			for _, b := range tt.writes {
+				if n, err := w.Write([]byte(b)); err != nil || n != len(b) {
+					t.Fatalf("Write(%q) = %d, %v", b, n, err)
+				}
+			}
+			if got := m.Sent(); !reflect.DeepEqual(got, tt.sent) {
+				t.Errorf("sent %q, want %q", got, tt.sent)
+			}
+			if got := string(w.buf); got != tt.left {
+				t.Errorf("buffered %q, want %q", got, tt.left)
+			}
+		})
+	}
+}
+
+func TestPrinterWriterFlush(t *testing.T) {
+	m := NewMockPrinter()
+	w := AsWriter(m)
+	w.Write([]byte("~HS"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Sent(); !reflect.DeepEqual(got, []string{"~HS"}) {
+		t.Errorf("sent %q, want the flushed ~HS", got)
+	}
+}
@@ -0,0 +1,66 @@
+package zpl
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// GraphicEncoding is how ImageToZPL and StoreGraphic write graphic data.
+type GraphicEncoding int
+
+// Graphic data encodings. The Base64 forms carry a CRC the printer checks
+// the data against, and need firmware from x.13 on.
+const (
+	GraphicHex GraphicEncoding = iota // uncompressed ASCII hex, two characters a byte
+	GraphicB64                        // Base64, four characters every three bytes
+	GraphicZ64                        // zlib compressed, then Base64
+)
+
+// encode writes data in e.
+func (e GraphicEncoding) encode(data []byte) (string, error) {
+	switch e {
+	case GraphicHex:
+		return strings.ToUpper(hex.EncodeToString(data)), nil
+	case GraphicB64:
+		return crcField("B64", base64.StdEncoding.EncodeToString(data)), nil
+	case GraphicZ64:
+		var buf bytes.Buffer
+		w, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+		if err != nil {
+			return "", err
+		}
+		w.Write(data)
+		if err := w.Close(); err != nil {
+			return "", fmt.Errorf("failed to compress graphic: %w", err)
+		}
+		return crcField("Z64", base64.StdEncoding.EncodeToString(buf.Bytes())), nil
+	}
+	return "", fmt.Errorf("invalid graphic encoding %d", e)
+}
+
+// crcField frames Base64 data as :kind:data:crc, with the CRC of the
+// Base64 text in four hex digits.
+func crcField(kind, b64 string) string {
+	return fmt.Sprintf(":%s:%s:%04X", kind, b64, crc16([]byte(b64)))
+}
+
+// crc16 computes the CRC-16/XMODEM checksum ZPL uses for B64 and Z64 data:
+// polynomial 0x1021, initial value 0, no reflection.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
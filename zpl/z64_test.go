@@ -0,0 +1,117 @@
+package zpl
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		data string
+		want uint16
+	}{
+		{"", 0},
+		{"123456789", 0x31C3}, // the CRC-16/XMODEM check value
+		{"MTIzNDU2Nzg5", 0xB3E6},
+		{"/wD/AA==", 0x98F1},
+	}
+	for _, tt := range tests {
+		if got := crc16([]byte(tt.data)); got != tt.want {
+			t.Errorf("crc16(%q) = %04X, want %04X", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestGraphicEncodingEncode(t *testing.T) {
+	tests := []struct {
+		enc  GraphicEncoding
+		data []byte
+		want string
+	}{
+		{GraphicHex, []byte{0xff, 0x00, 0x0a}, "FF000A"},
+		{GraphicB64, []byte("123456789"), ":B64:MTIzNDU2Nzg5:B3E6"},
+		{GraphicB64, []byte{0xff, 0x00, 0xff, 0x00}, ":B64:/wD/AA==:98F1"},
+	}
+	for _, tt := range tests {
+		got, err := tt.enc.encode(tt.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("encode(%d, %x) = %q, want %q", tt.enc, tt.data, got, tt.want)
+		}
+	}
+	if _, err := GraphicEncoding(9).encode(nil); err == nil {
+		t.Error("encode with an invalid encoding succeeded")
+	}
+}
+
+// decodeCRCField reverses crcField and the compression of a Z64 field,
+// checking the CRC on the way like a printer does.
+func decodeCRCField(field string) ([]byte, error) {
+	parts := strings.Split(field, ":")
+	if len(parts) != 4 || parts[0] != "" {
+		return nil, fmt.Errorf("malformed field %q", field)
+	}
+	crc, err := strconv.ParseUint(parts[3], 16, 16)
+	if err != nil {
+		return nil, err
+	}
+	if got := crc16([]byte(parts[2])); got != uint16(crc) {
+		return nil, fmt.Errorf("CRC is %04X, want %04X", crc, got)
+	}
+	data, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil || parts[1] == "B64" {
+		return data, err
+	}
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestGraphicEncodingRoundTrip(t *testing.T) {
+	// A 16x4 dot checkerboard and a mostly blank graphic, which compresses
+	blank := make([]byte, 200)
+	blank[100] = 0x80
+	for _, enc := range []GraphicEncoding{GraphicB64, GraphicZ64} {
+		for _, data := range [][]byte{
+			{0xaa, 0xaa, 0x55, 0x55, 0xaa, 0xaa, 0x55, 0x55},
+			blank,
+		} {
+			field, err := enc.encode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := decodeCRCField(field)
+			if err != nil {
+				t.Fatalf("decoding %q: %v", field, err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("%q decodes to %x, want %x", field, got, data)
+			}
+		}
+	}
+}
+
+func TestDecodeCRCFieldSample(t *testing.T) {
+	// The checkerboard above, compressed by Python's zlib rather than Go's
+	want := []byte{0xaa, 0xaa, 0x55, 0x55, 0xaa, 0xaa, 0x55, 0x55}
+	got, err := decodeCRCField(":Z64:eNpbtSo0dBUQAwATSgP9:03D1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("sample decodes to %x, want %x", got, want)
+	}
+	if _, err := decodeCRCField(":Z64:eNpbtSo0dBUQAwATSgP9:03D2"); err == nil {
+		t.Error("decoding a field with a bad CRC succeeded")
+	}
+}